@@ -0,0 +1,249 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "strconv"
+    "strings"
+)
+// Cycles enumerates the elementary cycles (i.e. not visiting a node twice) of
+// the network with Johnson's algorithm, by increasing length, up to maxLength
+// edges and up to maxCount cycles (NaN for no limit), so that maxCount keeps
+// the shortest cycles. If through is not empty, only the cycles passing
+// through at least one of these nodes are kept, the components without such
+// nodes being skipped. Each cycle starts and ends at its first node in order of
+// appearance in the network.
+func (network *Network) Cycles(through []string,maxLength,maxCount float64) []Path {
+    var (
+        start,node,length int
+        edgeCycle []int
+        allowed []bool
+        cycles []Path
+        finder *cycleFinder
+    )
+    finder=&cycleFinder{
+        network:network,
+        maxCount:maxCount,
+        blocked:make([]bool,len(network.nodes)),
+        blockers:make([][]int,len(network.nodes)),
+    }
+    if len(through)!=0 {
+        finder.through=network.nodeSet(through)
+    }
+    finder.cut=true
+    for length=1;finder.cut && !(float64(length)>maxLength) && !finder.isFull();length++ {
+        finder.length,finder.cut=length,false
+        for start=0;(start<len(network.nodes)) && !finder.isFull();start++ {
+            allowed=network.componentOf(start)
+            if (allowed!=nil) && finder.passesThrough(allowed) {
+                for node=range finder.blocked {
+                    finder.blocked[node]=false
+                    finder.blockers[node]=finder.blockers[node][:0]
+                }
+                finder.start,finder.allowed=start,allowed
+                finder.circuit(start)
+            }
+        }
+    }
+    for _,edgeCycle=range finder.cycles {
+        cycles=append(cycles,network.toPath(edgeCycle))
+    }
+    return cycles
+}
+// WriteCycles writes cycles to a TSV file with the columns length, nodes,
+// interactions and sign, plus weights if the cycles are weighted, laid out as
+// in WritePaths. The sign of a cycle is
+// positive or negative if all its edges are signed, according to signMap,
+// and unknown otherwise.
+func WriteCycles(cycleFile string,cycles []Path,signMap SignMap) error {
+    var (
+        weighted bool
+        sign string
+        names,steps,line []string
+        lines [][]string
+        cycle Path
+    )
+    for _,cycle=range cycles {
+        weighted=weighted || (cycle.Weights!=nil)
+    }
+    lines=append(lines,[]string{"length","nodes","interactions","sign"})
+    if weighted {
+        lines[0]=append(lines[0],"weights")
+    }
+    for _,cycle=range cycles {
+        steps=[]string{}
+        for _,names=range cycle.Names {
+            steps=append(steps,strings.Join(names,"|"))
+        }
+        sign="unknown"
+        if signMap.PathSign(cycle)==1 {
+            sign="positive"
+        } else if signMap.PathSign(cycle)==-1 {
+            sign="negative"
+        }
+        line=[]string{
+            strconv.Itoa(cycle.Length()),
+            strings.Join(cycle.Nodes,";"),
+            strings.Join(steps,";"),
+            sign,
+        }
+        if weighted {
+            line=append(line,joinWeights(cycle))
+        }
+        lines=append(lines,line)
+    }
+    return writeTable(cycleFile,lines)
+}
+// cycleFinder holds the state of Johnson's algorithm for a given start node
+// and a given cycle length: the search is restricted to the allowed nodes,
+// namely the strongly connected component of the start node among the nodes
+// following it, and to the paths of at most length edges, cut telling
+// whether some path was cut at this length.
+type cycleFinder struct {
+    network *Network
+    start,length int
+    maxCount float64
+    cut bool
+    allowed,through,blocked []bool
+    blockers [][]int
+    current []int
+    cycles [][]int
+}
+func (finder *cycleFinder) isFull() bool {
+    return float64(len(finder.cycles))>=finder.maxCount
+}
+// circuit extends the current path from node and tells whether it may lead
+// back to the start node, recording the cycles of the given length. A path
+// cut at this length is assumed to possibly lead back, so that the blocking of
+// Johnson's algorithm remains correct.
+func (finder *cycleFinder) circuit(node int) bool {
+    var (
+        found bool
+        edge,next int
+    )
+    finder.blocked[node]=true
+    for _,edge=range finder.network.succ[node] {
+        next=finder.network.edges[edge][1]
+        if finder.isFull() {
+            found=true
+            break
+        } else if finder.allowed[next] && (next==finder.start) {
+            found=true
+            if len(finder.current)+1==finder.length {
+                finder.record(append(copyInts(finder.current),edge))
+            }
+        } else if finder.allowed[next] && !finder.blocked[next] {
+            if len(finder.current)+1>=finder.length {
+                found=true
+                finder.cut=true
+            } else {
+                finder.current=append(finder.current,edge)
+                if finder.circuit(next) {
+                    found=true
+                }
+                finder.current=finder.current[:len(finder.current)-1]
+            }
+        }
+    }
+    if found {
+        finder.unblock(node)
+    } else {
+        for _,edge=range finder.network.succ[node] {
+            next=finder.network.edges[edge][1]
+            if finder.allowed[next] && !isInInts(finder.blockers[next],node) {
+                finder.blockers[next]=append(finder.blockers[next],node)
+            }
+        }
+    }
+    return found
+}
+func (finder *cycleFinder) unblock(node int) {
+    var (
+        blocker int
+        blockers []int
+    )
+    finder.blocked[node]=false
+    blockers=finder.blockers[node]
+    finder.blockers[node]=nil
+    for _,blocker=range blockers {
+        if finder.blocked[blocker] {
+            finder.unblock(blocker)
+        }
+    }
+}
+// passesThrough tells whether the allowed nodes include a through node, if
+// any, so that they may hold cycles to keep.
+func (finder *cycleFinder) passesThrough(allowed []bool) bool {
+    var (
+        found bool
+        node int
+    )
+    found=(finder.through==nil)
+    for node=0;(node<len(allowed)) && !found;node++ {
+        found=allowed[node] && finder.through[node]
+    }
+    return found
+}
+func (finder *cycleFinder) record(edgeCycle []int) {
+    var (
+        keep bool
+        edge int
+    )
+    keep=(finder.through==nil)
+    for _,edge=range edgeCycle {
+        keep=keep || finder.through[finder.network.edges[edge][0]]
+    }
+    if keep {
+        finder.cycles=append(finder.cycles,edgeCycle)
+    }
+}
+// componentOf returns the strongly connected component of start among the
+// nodes following it (as a boolean slice indexed by node ID), or nil if start
+// lies on no cycle within these nodes.
+func (network *Network) componentOf(start int) []bool {
+    var (
+        node,edge,next int
+        toCheck,newCheck []int
+        forward,component []bool
+        cyclic bool
+    )
+    forward=make([]bool,len(network.nodes))
+    newCheck=[]int{start}
+    forward[start]=true
+    for len(newCheck)!=0 {
+        toCheck=newCheck
+        newCheck=[]int{}
+        for _,node=range toCheck {
+            for _,edge=range network.succ[node] {
+                next=network.edges[edge][1]
+                if (next>=start) && !forward[next] {
+                    forward[next]=true
+                    newCheck=append(newCheck,next)
+                }
+            }
+        }
+    }
+    component=make([]bool,len(network.nodes))
+    component[start]=true
+    newCheck=[]int{start}
+    for len(newCheck)!=0 {
+        toCheck=newCheck
+        newCheck=[]int{}
+        for _,node=range toCheck {
+            for _,edge=range network.pred[node] {
+                next=network.edges[edge][0]
+                if next==start {
+                    cyclic=true
+                }
+                if forward[next] && !component[next] {
+                    component[next]=true
+                    newCheck=append(newCheck,next)
+                }
+            }
+        }
+    }
+    if !cyclic {
+        component=nil
+    }
+    return component
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "math"
+    "path/filepath"
+    "strconv"
+    "strings"
+    "testing"
+)
+// testCycleSIF is a network with a self-loop (E), a cycle of length 2 (A, B)
+// and a cycle of length 3 (B, C, D).
+const testCycleSIF="A\tactivation\tB\n"+
+    "B\tactivation\tA\n"+
+    "B\tactivation\tC\n"+
+    "C\tactivation\tD\n"+
+    "D\tinhibition\tB\n"+
+    "E\tactivation\tE\n"
+func TestCycles(t *testing.T) {
+    var (
+        i,j int
+        content string
+        network *Network
+        finder *cycleFinder
+        tests []struct {
+            through []string
+            maxLength,maxCount float64
+            cycles []string
+        }
+    )
+    network=readFixture(t,"cycles.sif",testCycleSIF,ReadOptions{})
+    tests=[]struct {
+        through []string
+        maxLength,maxCount float64
+        cycles []string
+    }{
+        // by increasing length
+        {nil,math.NaN(),math.NaN(),[]string{"E E","A B A","B C D B"}},
+        {nil,2,math.NaN(),[]string{"E E","A B A"}},
+        // the shortest cycles are kept
+        {nil,math.NaN(),2,[]string{"E E","A B A"}},
+        {[]string{"C"},math.NaN(),math.NaN(),[]string{"B C D B"}},
+        {[]string{"B","E"},math.NaN(),math.NaN(),[]string{"E E","A B A","B C D B"}},
+        {[]string{"C"},2,math.NaN(),[]string{}},
+    }
+    for i=range tests {
+        checkLists(t,"Cycles("+strings.Join(tests[i].through,",")+")",pathList(network.Cycles(tests[i].through,tests[i].maxLength,tests[i].maxCount)),tests[i].cycles)
+    }
+    checkLists(t,"Cycles",pathList(readFixture(t,"network.sif",testSIF,ReadOptions{}).Cycles(nil,math.NaN(),math.NaN())),[]string{"A B C A"})
+    // the complete network on N1 ... N9 has no through node, so that its
+    // numerous cycles are not enumerated
+    content=testCycleSIF
+    for i=1;i<=9;i++ {
+        for j=1;j<=9;j++ {
+            if i!=j {
+                content+="N"+strconv.Itoa(i)+"\tactivation\tN"+strconv.Itoa(j)+"\n"
+            }
+        }
+    }
+    network=readFixture(t,"cycles.sif",content,ReadOptions{})
+    finder=&cycleFinder{through:network.nodeSet([]string{"C"})}
+    if finder.passesThrough(network.componentOf(network.nodeIDs["N1"])) || !finder.passesThrough(network.componentOf(network.nodeIDs["B"])) {
+        t.Errorf("passesThrough: got N1 %v and B %v, want false and true",finder.passesThrough(network.componentOf(network.nodeIDs["N1"])),finder.passesThrough(network.componentOf(network.nodeIDs["B"])))
+    }
+    checkLists(t,"Cycles(C)",pathList(network.Cycles([]string{"C"},math.NaN(),math.NaN())),[]string{"B C D B"})
+}
+func TestWriteCycles(t *testing.T) {
+    var (
+        network *Network
+    )
+    network=readFixture(t,"cycles.sif",testCycleSIF+"F\tbinding\tF\n",ReadOptions{})
+    checkFile(t,"WriteCycles",func(file string) error {return WriteCycles(file,network.Cycles(nil,math.NaN(),math.NaN()),SignMap{})},"cycles.tsv",
+        "length\tnodes\tinteractions\tsign\n"+
+        "1\tE;E\tactivation\tpositive\n"+
+        "1\tF;F\tbinding\tunknown\n"+
+        "2\tA;B;A\tactivation;activation\tpositive\n"+
+        "3\tB;C;D;B\tactivation;activation;inhibition\tnegative\n",
+    )
+    network=readFixture(t,"cycles.sif","A\tactivation\tB\t2\nB\tinhibition\tA\t0.5\n",ReadOptions{Weighted:true})
+    checkFile(t,"WriteCycles",func(file string) error {return WriteCycles(file,network.Cycles(nil,math.NaN(),math.NaN()),SignMap{})},"cycles.tsv",
+        "length\tnodes\tinteractions\tsign\tweights\n"+
+        "2\tA;B;A\tactivation;inhibition\tnegative\t2;0.5\n",
+    )
+    if WriteCycles(filepath.Join(t.TempDir(),"cycles.tsv"),nil,SignMap{})==nil {
+        t.Errorf("WriteCycles: no error without cycles")
+    }
+}
@@ -0,0 +1,235 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "encoding/json"
+    "errors"
+    "math"
+    "strconv"
+)
+// cytoscapeElement and the following types hold the parts of the cytoscape.js
+// and CX2 files written by WriteCytoscape and WriteCX2.
+type cytoscapeElement struct {
+    Data map[string]interface{} `json:"data"`
+}
+type cytoscapeElements struct {
+    Nodes []cytoscapeElement `json:"nodes"`
+    Edges []cytoscapeElement `json:"edges"`
+}
+type cx2Node struct {
+    ID int `json:"id"`
+    V map[string]interface{} `json:"v"`
+}
+type cx2Edge struct {
+    ID int `json:"id"`
+    S int `json:"s"`
+    T int `json:"t"`
+    V map[string]interface{} `json:"v"`
+}
+type cx2Type struct {
+    D string `json:"d"`
+}
+type cx2Count struct {
+    Name string `json:"name"`
+    ElementCount int `json:"elementCount"`
+}
+// annotatedEdge is an interaction name of an edge along with its data.
+type annotatedEdge struct {
+    edge int
+    data map[string]interface{}
+}
+// WriteCytoscape writes the network to a JSON file in the cytoscape.js format
+// (elements), which Cytoscape imports directly, one edge per interaction name,
+// the nodes and the edges being annotated as described in annotations.
+func (network *Network) WriteCytoscape(jsonFile string,highlight Highlight) error {
+    var (
+        err error
+        i int
+        data []byte
+        nodeData []map[string]interface{}
+        edgeData []annotatedEdge
+        elements cytoscapeElements
+    )
+    if len(network.edges)==0 {
+        err=errors.New("empty before writing")
+    } else {
+        nodeData,edgeData=network.annotations(highlight)
+        for i=range nodeData {
+            nodeData[i]["id"]=network.nodes[i]
+            elements.Nodes=append(elements.Nodes,cytoscapeElement{Data:nodeData[i]})
+        }
+        for i=range edgeData {
+            edgeData[i].data["id"]="e"+strconv.Itoa(i)
+            edgeData[i].data["source"]=network.nodes[network.edges[edgeData[i].edge][0]]
+            edgeData[i].data["target"]=network.nodes[network.edges[edgeData[i].edge][1]]
+            elements.Edges=append(elements.Edges,cytoscapeElement{Data:edgeData[i].data})
+        }
+        data,err=json.MarshalIndent(map[string]cytoscapeElements{"elements":elements},"","    ")
+        if err==nil {
+            err=writeFile(jsonFile,append(data,'\n'))
+        }
+    }
+    return err
+}
+// WriteCX2 writes the network to a CX2 file (the JSON format of Cytoscape and
+// NDEx), one edge per interaction name, the nodes and the edges being
+// annotated as described in annotations. The attributes read from GraphML
+// files are declared as strings.
+func (network *Network) WriteCX2(cxFile string,highlight Highlight) error {
+    var (
+        err error
+        i int
+        data []byte
+        nodeData []map[string]interface{}
+        edgeData []annotatedEdge
+        nodes []cx2Node
+        edges []cx2Edge
+        nodeTypes,edgeTypes map[string]cx2Type
+    )
+    if len(network.edges)==0 {
+        err=errors.New("empty before writing")
+    } else {
+        nodeData,edgeData=network.annotations(highlight)
+        nodeTypes=make(map[string]cx2Type)
+        edgeTypes=make(map[string]cx2Type)
+        for i=range nodeData {
+            declareTypes(nodeTypes,nodeData[i])
+            nodes=append(nodes,cx2Node{ID:i,V:nodeData[i]})
+        }
+        for i=range edgeData {
+            declareTypes(edgeTypes,edgeData[i].data)
+            edges=append(edges,cx2Edge{ID:i,S:network.edges[edgeData[i].edge][0],T:network.edges[edgeData[i].edge][1],V:edgeData[i].data})
+        }
+        data,err=json.MarshalIndent([]interface{}{
+            map[string]interface{}{"CXVersion":"2.0","hasFragments":false},
+            map[string][]cx2Count{"metaData":{
+                {Name:"attributeDeclarations",ElementCount:1},
+                {Name:"nodes",ElementCount:len(nodes)},
+                {Name:"edges",ElementCount:len(edges)},
+            }},
+            map[string][]map[string]map[string]cx2Type{"attributeDeclarations":{{"nodes":nodeTypes,"edges":edgeTypes}}},
+            map[string][]cx2Node{"nodes":nodes},
+            map[string][]cx2Edge{"edges":edges},
+            map[string][]map[string]interface{}{"status":{{"error":"","success":true}}},
+        },"","    ")
+        if err==nil {
+            err=writeFile(cxFile,append(data,'\n'))
+        }
+    }
+    return err
+}
+// annotations returns the data of the nodes and of the interaction names
+// written by WriteCytoscape and WriteCX2. Along with the attributes read from
+// GraphML files, a node has a name, a role (source, target, source/target,
+// seed, terminal or intermediate, after highlight) and, if it is reachable,
+// its distance in number of edges from the source or seed nodes (in the
+// direction of highlight for the seed nodes). An interaction has a name
+// (source (interaction) target), an interaction, a weight if the network is
+// weighted, and a shortest attribute telling whether it lies on a shortest
+// path from the source nodes to the target nodes or, without target nodes,
+// on a shortest path (in number of edges) from the seed nodes.
+func (network *Network) annotations(highlight Highlight) ([]map[string]interface{},[]annotatedEdge) {
+    var (
+        node,edge,i int
+        name,key,value,role string
+        forward,backward,distances []float64
+        sources,targets,seeds,terminals,shortest []bool
+        nodeData []map[string]interface{}
+        edgeData []annotatedEdge
+        data map[string]interface{}
+    )
+    sources=network.nodeSet(highlight.Sources)
+    targets=network.nodeSet(highlight.Targets)
+    seeds=network.nodeSet(highlight.Seeds)
+    terminals=network.nodeSet(highlight.Terminals)
+    forward=network.distancesFrom(network.nodeList(append(copyList(highlight.Sources),highlight.Seeds...)))
+    backward=network.distancesTo(network.nodeList(highlight.Seeds))
+    distances=forward
+    if highlight.Direction=="up" {
+        distances=backward
+    } else if highlight.Direction=="both" {
+        distances=make([]float64,len(network.nodes))
+        for node=range network.nodes {
+            distances[node]=math.Min(forward[node],backward[node])
+        }
+    }
+    shortest=make([]bool,len(network.edges))
+    if len(highlight.Targets)!=0 {
+        for _,edge=range network.allShortestPaths(network.nodeList(highlight.Sources),network.nodeList(highlight.Targets)) {
+            shortest[edge]=true
+        }
+    } else {
+        for edge=range network.edges {
+            if highlight.Direction!="up" {
+                shortest[edge]=!math.IsInf(forward[network.edges[edge][0]],1) && (forward[network.edges[edge][1]]==forward[network.edges[edge][0]]+1)
+            }
+            if (highlight.Direction=="up") || (highlight.Direction=="both") {
+                shortest[edge]=shortest[edge] || (!math.IsInf(backward[network.edges[edge][1]],1) && (backward[network.edges[edge][0]]==backward[network.edges[edge][1]]+1))
+            }
+        }
+    }
+    for node=range network.nodes {
+        data=make(map[string]interface{})
+        for key,value=range network.nodeAttributes[node] {
+            data[key]=value
+        }
+        if sources[node] && targets[node] {
+            role="source/target"
+        } else if sources[node] {
+            role="source"
+        } else if targets[node] {
+            role="target"
+        } else if seeds[node] {
+            role="seed"
+        } else if terminals[node] {
+            role="terminal"
+        } else {
+            role="intermediate"
+        }
+        data["name"]=network.nodes[node]
+        data["role"]=role
+        if !math.IsInf(distances[node],1) {
+            data["distance"]=int(distances[node])
+        }
+        nodeData=append(nodeData,data)
+    }
+    for edge=range network.edges {
+        for i,name=range network.edgeNames[edge] {
+            data=make(map[string]interface{})
+            for key,value=range network.edgeAttributes[edge][i] {
+                data[key]=value
+            }
+            data["name"]=network.nodes[network.edges[edge][0]]+" ("+name+") "+network.nodes[network.edges[edge][1]]
+            data["interaction"]=name
+            if network.weighted {
+                data["weight"]=network.weights[edge][i]
+            }
+            data["shortest"]=shortest[edge]
+            edgeData=append(edgeData,annotatedEdge{edge:edge,data:data})
+        }
+    }
+    return nodeData,edgeData
+}
+// declareTypes adds to types the CX2 data types of the given attribute values.
+func declareTypes(types map[string]cx2Type,data map[string]interface{}) {
+    var (
+        isInt,isFloat,isBool bool
+        key string
+        value interface{}
+    )
+    for key,value=range data {
+        _,isInt=value.(int)
+        _,isFloat=value.(float64)
+        _,isBool=value.(bool)
+        if isInt {
+            types[key]=cx2Type{D:"integer"}
+        } else if isFloat {
+            types[key]=cx2Type{D:"double"}
+        } else if isBool {
+            types[key]=cx2Type{D:"boolean"}
+        } else {
+            types[key]=cx2Type{D:"string"}
+        }
+    }
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "testing"
+)
+// testCytoscapeSIF connects S to T directly and through A, A leading to B
+// outside of the shortest paths.
+const testCytoscapeSIF="S\tactivation\tA\n"+
+    "A\tactivation\tT\n"+
+    "S\tinhibition\tT\n"+
+    "A\tbinding\tB\n"
+func TestWriteCytoscape(t *testing.T) {
+    var (
+        i int
+        file string
+        network *Network
+        elements map[string]cytoscapeElements
+        tests []struct {
+            highlight Highlight
+            nodes,edges []string
+        }
+    )
+    network=readFixture(t,"network.sif",testCytoscapeSIF,ReadOptions{})
+    tests=[]struct {
+        highlight Highlight
+        nodes,edges []string
+    }{
+        // node: name role distance, edge: name shortest
+        {Highlight{Sources:[]string{"S"},Targets:[]string{"T"}},[]string{"S source 0","A intermediate 1","T target 1","B intermediate 2"},[]string{"S (activation) A false","A (activation) T false","S (inhibition) T true","A (binding) B false"}},
+        {Highlight{Seeds:[]string{"S"},Direction:"down"},[]string{"S seed 0","A intermediate 1","T intermediate 1","B intermediate 2"},[]string{"S (activation) A true","A (activation) T false","S (inhibition) T true","A (binding) B true"}},
+        {Highlight{Seeds:[]string{"T"},Terminals:[]string{"S"},Direction:"up"},[]string{"S terminal 1","A intermediate 1","T seed 0","B intermediate <nil>"},[]string{"S (activation) A false","A (activation) T true","S (inhibition) T true","A (binding) B false"}},
+    }
+    for i=range tests {
+        file=filepath.Join(t.TempDir(),"out.json")
+        checkError(t,"WriteCytoscape",network.WriteCytoscape(file,tests[i].highlight),"")
+        elements=nil
+        readJSON(t,file,&elements)
+        checkLists(t,"WriteCytoscape",elementList(elements["elements"].Nodes,"name","role","distance"),tests[i].nodes)
+        checkLists(t,"WriteCytoscape",elementList(elements["elements"].Edges,"name","shortest"),tests[i].edges)
+    }
+    checkLists(t,"WriteCytoscape",elementList(elements["elements"].Edges,"id","source","target","interaction"),[]string{"e0 S A activation","e1 A T activation","e2 S T inhibition","e3 A B binding"})
+    checkError(t,"WriteCytoscape",New().WriteCytoscape(file,Highlight{}),"empty before writing")
+}
+func TestWriteCX2(t *testing.T) {
+    var (
+        file string
+        network *Network
+        aspects []map[string]json.RawMessage
+        counts []cx2Count
+        declarations []map[string]map[string]cx2Type
+        nodes []cx2Node
+        edges []cx2Edge
+    )
+    network=readFixture(t,"network.sif","S\tactivation\tA\t1\nA\tactivation\tT\t1\nS\tinhibition\tT\t3\nA\tbinding\tB\t1\n",ReadOptions{Weighted:true})
+    file=filepath.Join(t.TempDir(),"out.cx2")
+    checkError(t,"WriteCX2",network.WriteCX2(file,Highlight{Sources:[]string{"S"},Targets:[]string{"T"}}),"")
+    readJSON(t,file,&aspects)
+    if len(aspects)!=6 {
+        t.Fatalf("WriteCX2: got %v aspects, want 6",len(aspects))
+    }
+    unmarshal(t,aspects[1]["metaData"],&counts)
+    unmarshal(t,aspects[2]["attributeDeclarations"],&declarations)
+    unmarshal(t,aspects[3]["nodes"],&nodes)
+    unmarshal(t,aspects[4]["edges"],&edges)
+    if (len(counts)!=3) || (counts[1].ElementCount!=4) || (counts[2].ElementCount!=4) {
+        t.Errorf("WriteCX2: got metaData %v, want 4 nodes and 4 edges",counts)
+    }
+    if (declarations[0]["nodes"]["distance"].D!="integer") || (declarations[0]["nodes"]["role"].D!="string") || (declarations[0]["edges"]["weight"].D!="double") || (declarations[0]["edges"]["shortest"].D!="boolean") {
+        t.Errorf("WriteCX2: got attribute declarations %v",declarations)
+    }
+    if (len(nodes)!=4) || (nodes[2].V["name"]!="T") || (nodes[2].V["role"]!="target") {
+        t.Errorf("WriteCX2: got nodes %v, want T as the third node, target",nodes)
+    }
+    // S -> A -> T weighs 2, S -> T weighs 3
+    if (len(edges)!=4) || (edges[2].S!=0) || (edges[2].T!=2) || (edges[2].V["weight"]!=3.0) || (edges[2].V["shortest"]!=false) || (edges[0].V["shortest"]!=true) {
+        t.Errorf("WriteCX2: got edges %v, want S -> T of weight 3 as the third edge, not the shortest",edges)
+    }
+    checkError(t,"WriteCX2",New().WriteCX2(file,Highlight{}),"empty before writing")
+}
+func readJSON(t *testing.T,file string,value interface{}) {
+    var (
+        err error
+        data []byte
+    )
+    t.Helper()
+    data,err=os.ReadFile(file)
+    if err!=nil {
+        t.Fatal(err)
+    }
+    unmarshal(t,data,value)
+}
+func unmarshal(t *testing.T,data []byte,value interface{}) {
+    var (
+        err error
+    )
+    t.Helper()
+    err=json.Unmarshal(data,value)
+    if err!=nil {
+        t.Fatal(err)
+    }
+}
+// elementList returns the given data of the elements, separated by spaces.
+func elementList(elements []cytoscapeElement,keys ...string) []string {
+    var (
+        i int
+        key,element string
+        list []string
+    )
+    list=[]string{}
+    for i=range elements {
+        element=""
+        for _,key=range keys {
+            if element!="" {
+                element+=" "
+            }
+            element+=fmt.Sprint(elements[i].Data[key])
+        }
+        list=append(list,element)
+    }
+    return list
+}
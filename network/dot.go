@@ -0,0 +1,97 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "errors"
+    "os"
+    "strings"
+)
+// Highlight lists the nodes playing a particular role in a result network, so
+// that they can be styled or annotated when writing it.
+type Highlight struct {
+    Sources,Targets,Seeds,Terminals []string
+    // Direction is the stream direction of the seed nodes (up, down or both).
+    Direction string
+    // BlackNeighbours are the nodes which were adjacent to blacklisted nodes.
+    BlackNeighbours []string
+    // Signs are used to draw activations and inhibitions (keywords if nil).
+    Signs SignMap
+}
+// WriteDOT writes the network to a Graphviz DOT file, styling the highlighted
+// nodes and drawing inhibitions as tees, so that "dot -Tsvg" directly renders
+// it.
+func (network *Network) WriteDOT(dotFile string,highlight Highlight) error {
+    var (
+        err error
+        positive,negative bool
+        node,edge int
+        attrs,lines []string
+        sources,targets,seeds,terminals,blackNeighbours []bool
+        file *os.File
+    )
+    sources=network.nodeSet(highlight.Sources)
+    targets=network.nodeSet(highlight.Targets)
+    seeds=network.nodeSet(highlight.Seeds)
+    terminals=network.nodeSet(highlight.Terminals)
+    blackNeighbours=network.nodeSet(highlight.BlackNeighbours)
+    lines=append(lines,
+        "digraph {",
+        "    node [shape=box, style=\"rounded,filled\", fillcolor=\"#c3d4e8\"];",
+        "    edge [color=\"#316ac4\"];",
+    )
+    for node=range network.nodes {
+        attrs=[]string{}
+        if sources[node] && targets[node] {
+            attrs=append(attrs,"fillcolor=\"#99ff99:#ff9999\"")
+        } else if sources[node] {
+            attrs=append(attrs,"fillcolor=\"#99ff99\"")
+        } else if targets[node] {
+            attrs=append(attrs,"fillcolor=\"#ff9999\"")
+        } else if seeds[node] {
+            attrs=append(attrs,"fillcolor=\"#ffcc66\"")
+        }
+        if blackNeighbours[node] {
+            attrs=append(attrs,"style=\"rounded,filled,dashed\"")
+        }
+        if terminals[node] {
+            attrs=append(attrs,"peripheries=2")
+        }
+        lines=append(lines,"    "+dotID(network.nodes[node])+dotAttrs(attrs)+";")
+    }
+    for edge=range network.edges {
+        attrs=[]string{"tooltip="+dotID(strings.Join(network.edgeNames[edge],"; "))}
+        positive,negative=highlight.Signs.signs(network.edgeNames[edge])
+        if positive && negative {
+            attrs=append(attrs,"arrowhead=odot")
+        } else if negative {
+            attrs=append(attrs,"arrowhead=tee")
+        } else if !positive {
+            attrs=append(attrs,"style=dashed")
+        }
+        lines=append(lines,"    "+dotID(network.nodes[network.edges[edge][0]])+" -> "+dotID(network.nodes[network.edges[edge][1]])+dotAttrs(attrs)+";")
+    }
+    lines=append(lines,"}")
+    if len(network.edges)==0 {
+        err=errors.New("empty before writing")
+    } else {
+        file,err=createFile(dotFile)
+        defer closeFile(file)
+        if err==nil {
+            _,err=file.WriteString(strings.Join(lines,"\n")+"\n")
+        }
+    }
+    return err
+}
+func dotAttrs(attrs []string) string {
+    var (
+        y string
+    )
+    if len(attrs)!=0 {
+        y=" ["+strings.Join(attrs,", ")+"]"
+    }
+    return y
+}
+func dotID(id string) string {
+    return "\""+strings.NewReplacer("\\","\\\\","\"","\\\"").Replace(id)+"\""
+}
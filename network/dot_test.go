@@ -0,0 +1,39 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "path/filepath"
+    "testing"
+)
+func TestWriteDOT(t *testing.T) {
+    var (
+        network *Network
+        highlight Highlight
+    )
+    network=readFixture(t,"network.sif","A\tactivation\tB\nA\tinhibition\tB\nB\tinhibition\tC\nC\tbinding\t\"D\"\"q\"\n\"D\"\"q\"\texpression\tA\n",ReadOptions{})
+    highlight=Highlight{
+        Sources:[]string{"A"},
+        Targets:[]string{"A","C"},
+        Seeds:[]string{"B"},
+        Terminals:[]string{"D\"q"},
+        BlackNeighbours:[]string{"C"},
+    }
+    checkFile(t,"WriteDOT",func(file string) error {return network.WriteDOT(file,highlight)},"out.dot",
+        "digraph {\n"+
+        "    node [shape=box, style=\"rounded,filled\", fillcolor=\"#c3d4e8\"];\n"+
+        "    edge [color=\"#316ac4\"];\n"+
+        "    \"A\" [fillcolor=\"#99ff99:#ff9999\"];\n"+
+        "    \"B\" [fillcolor=\"#ffcc66\"];\n"+
+        "    \"C\" [fillcolor=\"#ff9999\", style=\"rounded,filled,dashed\"];\n"+
+        "    \"D\\\"q\" [peripheries=2];\n"+
+        "    \"A\" -> \"B\" [tooltip=\"activation; inhibition\", arrowhead=odot];\n"+
+        "    \"B\" -> \"C\" [tooltip=\"inhibition\", arrowhead=tee];\n"+
+        "    \"C\" -> \"D\\\"q\" [tooltip=\"binding\", style=dashed];\n"+
+        "    \"D\\\"q\" -> \"A\" [tooltip=\"expression\"];\n"+
+        "}\n",
+    )
+    if New().WriteDOT(filepath.Join(t.TempDir(),"out.dot"),Highlight{})==nil {
+        t.Errorf("WriteDOT: no error for an empty network")
+    }
+}
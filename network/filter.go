@@ -0,0 +1,137 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "encoding/csv"
+    "errors"
+    "os"
+    "regexp"
+    "strings"
+)
+// CompilePattern compiles a pattern matching interaction names: "/regexp/" is
+// a regular expression, a pattern containing "*" or "?" is a glob ("*" matches
+// any string, "?" any character) and anything else is a literal name.
+func CompilePattern(pattern string) (*regexp.Regexp,error) {
+    var (
+        err error
+        expr string
+        compiled *regexp.Regexp
+    )
+    if (len(pattern)>=2) && strings.HasPrefix(pattern,"/") && strings.HasSuffix(pattern,"/") {
+        expr=pattern[1:len(pattern)-1]
+    } else if strings.ContainsAny(pattern,"*?") {
+        expr=regexp.QuoteMeta(pattern)
+        expr=strings.ReplaceAll(expr,"\\*",".*")
+        expr=strings.ReplaceAll(expr,"\\?",".")
+        expr="^"+expr+"$"
+    } else {
+        expr="^"+regexp.QuoteMeta(pattern)+"$"
+    }
+    compiled,err=regexp.Compile(expr)
+    if err!=nil {
+        err=errors.New(pattern+": "+err.Error())
+    }
+    return compiled,err
+}
+// ReadPatterns reads a list of interaction name patterns (one pattern per
+// line), as understood by CompilePattern.
+func ReadPatterns(patternFile string) ([]*regexp.Regexp,error) {
+    var (
+        err error
+        line []string
+        lines [][]string
+        compiled *regexp.Regexp
+        patterns []*regexp.Regexp
+        file *os.File
+        reader *csv.Reader
+    )
+    file,err=openFile(patternFile)
+    defer closeFile(file)
+    if err==nil {
+        reader=csv.NewReader(file)
+        reader.Comma='\t'
+        reader.Comment=0
+        reader.FieldsPerRecord=1
+        reader.LazyQuotes=true
+        reader.TrimLeadingSpace=true
+        reader.ReuseRecord=true
+        lines,err=reader.ReadAll()
+        if err==nil {
+            for _,line=range lines {
+                compiled,err=CompilePattern(line[0])
+                if err!=nil {
+                    break
+                }
+                patterns=append(patterns,compiled)
+            }
+            if (err==nil) && (len(patterns)==0) {
+                err=errors.New("empty after reading")
+            }
+        }
+    }
+    return patterns,err
+}
+// FilterInteractions returns the network keeping only the interaction names
+// matching some include pattern (all if there are none) and no exclude
+// pattern, and the edges still having interaction names. Names joining several
+// interactions with commas (e.g. "activation_PPrel,phosphorylation_PPrel") are
+// kept if any of their parts is, a pattern matching the whole name matching all
+// its parts. The nodes are all kept, even if left without any edge.
+func (network *Network) FilterInteractions(include,exclude []*regexp.Regexp) (*Network,error) {
+    var (
+        err error
+        i,edge int
+        node,name string
+        newNetwork *Network
+    )
+    newNetwork=New()
+    newNetwork.weighted=network.weighted
+    newNetwork.confidence=network.confidence
+    for _,node=range network.nodes {
+        newNetwork.addNode(node)
+        newNetwork.copyNodeAttributes(network,node)
+    }
+    for edge=range network.edges {
+        for i,name=range network.edgeNames[edge] {
+            if keepInteraction(name,include,exclude) {
+                newNetwork.copyName(network,edge,i)
+            }
+        }
+    }
+    if len(newNetwork.edges)==0 {
+        err=errors.New("network empty after filtering interactions")
+    }
+    return newNetwork,err
+}
+func keepInteraction(name string,include,exclude []*regexp.Regexp) bool {
+    var (
+        keep,whole bool
+        part string
+    )
+    keep=false
+    whole=(len(include)==0) || matchAny(include,name)
+    if !matchAny(exclude,name) {
+        for _,part=range strings.Split(name,",") {
+            if (whole || matchAny(include,part)) && !matchAny(exclude,part) {
+                keep=true
+                break
+            }
+        }
+    }
+    return keep
+}
+func matchAny(patterns []*regexp.Regexp,name string) bool {
+    var (
+        found bool
+        pattern *regexp.Regexp
+    )
+    found=false
+    for _,pattern=range patterns {
+        if pattern.MatchString(name) {
+            found=true
+            break
+        }
+    }
+    return found
+}
@@ -0,0 +1,112 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "regexp"
+    "strings"
+    "testing"
+)
+func TestCompilePattern(t *testing.T) {
+    var (
+        i int
+        err error
+        compiled *regexp.Regexp
+        tests []struct {
+            pattern,name string
+            match bool
+        }
+    )
+    tests=[]struct {
+        pattern,name string
+        match bool
+    }{
+        {"activation","activation",true},
+        {"activation","activation_PPrel",false},
+        {"a.tivation","activation",false},
+        {"*_PPrel","activation_PPrel",true},
+        {"*_PPrel","activation_GErel",false},
+        {"?nhibition","inhibition",true},
+        {"?nhibition","xinhibition",false},
+        {"/PP|GE/","activation_GErel",true},
+        {"/^PP/","activation_PPrel",false},
+    }
+    for i=range tests {
+        compiled,err=CompilePattern(tests[i].pattern)
+        if err!=nil {
+            t.Errorf("CompilePattern(%q): %v",tests[i].pattern,err)
+        } else if compiled.MatchString(tests[i].name)!=tests[i].match {
+            t.Errorf("CompilePattern(%q): matching %q gives %v, want %v",tests[i].pattern,tests[i].name,!tests[i].match,tests[i].match)
+        }
+    }
+    _,err=CompilePattern("/(/")
+    checkError(t,"CompilePattern",err,"/(/: error parsing regexp")
+}
+func TestReadPatterns(t *testing.T) {
+    var (
+        err error
+        patterns []*regexp.Regexp
+    )
+    patterns,err=ReadPatterns(writeFixture(t,"patterns.txt","activation\n*_PPrel\n/GE/\n"))
+    checkError(t,"ReadPatterns",err,"")
+    if len(patterns)!=3 {
+        t.Errorf("ReadPatterns: got %v patterns, want 3",len(patterns))
+    }
+    _,err=ReadPatterns(writeFixture(t,"patterns.txt","activation\n/(/\n"))
+    checkError(t,"ReadPatterns",err,"error parsing regexp")
+    _,err=ReadPatterns(writeFixture(t,"patterns.txt",""))
+    checkError(t,"ReadPatterns",err,"empty after reading")
+}
+func TestFilterInteractions(t *testing.T) {
+    var (
+        i int
+        err error
+        network,filtered *Network
+        tests []struct {
+            include,exclude []string
+            lines []string
+            err string
+        }
+    )
+    network=readFixture(t,"network.sif","A\tactivation\tB\nA\tinhibition\tB\nB\tbinding\tC\nC\tactivation_PPrel,phosphorylation_PPrel\tD\n",ReadOptions{})
+    tests=[]struct {
+        include,exclude []string
+        lines []string
+        err string
+    }{
+        {[]string{"activation"},nil,[]string{"A activation B"},""},
+        // a name joining several interactions is kept if any of its parts is
+        {[]string{"*activation*"},nil,[]string{"A activation B","C activation_PPrel,phosphorylation_PPrel D"},""},
+        {[]string{"phosphorylation_PPrel"},nil,[]string{"C activation_PPrel,phosphorylation_PPrel D"},""},
+        {nil,[]string{"phosphorylation_PPrel"},[]string{"A activation B","A inhibition B","B binding C","C activation_PPrel,phosphorylation_PPrel D"},""},
+        // a pattern matching the whole name matches all its parts
+        {nil,[]string{"/PPrel/"},[]string{"A activation B","A inhibition B","B binding C"},""},
+        {[]string{"*ion"},[]string{"inhibition"},[]string{"A activation B"},""},
+        {nil,[]string{"*"},nil,"network empty after filtering interactions"},
+    }
+    for i=range tests {
+        filtered,err=network.FilterInteractions(compilePatterns(t,tests[i].include),compilePatterns(t,tests[i].exclude))
+        checkError(t,"FilterInteractions("+strings.Join(tests[i].include,",")+"; "+strings.Join(tests[i].exclude,",")+")",err,tests[i].err)
+        if err==nil {
+            checkLists(t,"FilterInteractions",filtered.Nodes(),[]string{"A","B","C","D"})
+            checkSets(t,"FilterInteractions("+strings.Join(tests[i].include,",")+"; "+strings.Join(tests[i].exclude,",")+")",sifLines(filtered),tests[i].lines)
+        }
+    }
+}
+func compilePatterns(t *testing.T,patterns []string) []*regexp.Regexp {
+    var (
+        err error
+        pattern string
+        compiled *regexp.Regexp
+        list []*regexp.Regexp
+    )
+    t.Helper()
+    for _,pattern=range patterns {
+        compiled,err=CompilePattern(pattern)
+        if err!=nil {
+            t.Fatal(err)
+        }
+        list=append(list,compiled)
+    }
+    return list
+}
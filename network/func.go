@@ -0,0 +1,249 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+
+// WARNING The functions in the present file do not fully handle exceptions and
+// errors. Instead, they assume that such handling is performed upstream by the
+// exported methods of Network, which is why they are not exported.
+
+// Nodes and edges are handled through their IDs, namely their indices in
+// network.nodes and network.edges. Sets of edges are slices of edge IDs paired
+// with a boolean slice indexed by edge ID for constant-time membership.
+
+package network
+import (
+    "math"
+)
+func (network *Network) allShortestPaths(sources,targets []int) []int {
+    var (
+        source,target,edge int
+        shortest,allShortest []int
+        layerPred [][]int
+        inAll []bool
+    )
+    inAll=make([]bool,len(network.edges))
+    for _,source=range sources {
+        if network.weighted {
+            layerPred=network.getWeightedLayers(source)
+        } else {
+            layerPred=network.getLayers(source)
+        }
+        for _,target=range targets {
+            if (source==target) && network.isSelfLooped(source) {
+                shortest=[]int{network.edgeIDs[[2]int{source,target}]}
+            } else if network.weighted {
+                shortest=network.weightedShortestPaths(source,target,layerPred)
+            } else {
+                shortest=network.shortestPaths(source,target,layerPred)
+            }
+            for _,edge=range shortest {
+                if !inAll[edge] {
+                    inAll[edge]=true
+                    allShortest=append(allShortest,edge)
+                }
+            }
+        }
+    }
+    return allShortest
+}
+func (network *Network) backwardEdges(seeds []int,depth float64) []int {
+    var (
+        d float64
+        seed,edge,epred int
+        backward,newCheck,toCheck []int
+        inBackward []bool
+    )
+    inBackward=make([]bool,len(network.edges))
+    d=1
+    for _,seed=range seeds {
+        for _,epred=range network.pred[seed] {
+            if !inBackward[epred] {
+                inBackward[epred]=true
+                backward=append(backward,epred)
+                newCheck=append(newCheck,epred)
+            }
+        }
+    }
+    for (len(newCheck)!=0) && (d!=depth) {
+        d+=1
+        toCheck=newCheck
+        newCheck=[]int{}
+        for _,edge=range toCheck {
+            for _,epred=range network.pred[network.edges[edge][0]] {
+                if !inBackward[epred] {
+                    inBackward[epred]=true
+                    backward=append(backward,epred)
+                    newCheck=append(newCheck,epred)
+                }
+            }
+        }
+    }
+    return backward
+}
+// connectEdges returns the edges lying on the paths connecting the source
+// nodes to the target nodes, namely the forward edges of the source nodes which
+// are also backward edges of the target nodes.
+func (network *Network) connectEdges(sources,targets []int) []int {
+    var (
+        edge int
+        connect []int
+        inBackward []bool
+    )
+    inBackward=make([]bool,len(network.edges))
+    for _,edge=range network.backwardEdges(targets,math.NaN()) {
+        inBackward[edge]=true
+    }
+    for _,edge=range network.forwardEdges(sources,math.NaN()) {
+        if inBackward[edge] {
+            connect=append(connect,edge)
+        }
+    }
+    return connect
+}
+func (network *Network) forwardEdges(seeds []int,depth float64) []int {
+    var (
+        d float64
+        seed,edge,esucc int
+        forward,newCheck,toCheck []int
+        inForward []bool
+    )
+    inForward=make([]bool,len(network.edges))
+    d=1
+    for _,seed=range seeds {
+        for _,esucc=range network.succ[seed] {
+            if !inForward[esucc] {
+                inForward[esucc]=true
+                forward=append(forward,esucc)
+                newCheck=append(newCheck,esucc)
+            }
+        }
+    }
+    for (len(newCheck)!=0) && (d!=depth) {
+        d+=1
+        toCheck=newCheck
+        newCheck=[]int{}
+        for _,edge=range toCheck {
+            for _,esucc=range network.succ[network.edges[edge][1]] {
+                if !inForward[esucc] {
+                    inForward[esucc]=true
+                    forward=append(forward,esucc)
+                    newCheck=append(newCheck,esucc)
+                }
+            }
+        }
+    }
+    return forward
+}
+// getLayers performs a breadth-first search from seed, ignoring self-loops,
+// and returns for each node the edges through which the search first reached
+// it (the seed itself is only marked visited once reached back).
+func (network *Network) getLayers(seed int) [][]int {
+    var (
+        edge,esucc int
+        layer,newLayer []int
+        layerPred [][]int
+        visited,inLayers []bool
+    )
+    layerPred=make([][]int,len(network.nodes))
+    visited=make([]bool,len(network.nodes))
+    inLayers=make([]bool,len(network.edges))
+    for _,esucc=range network.succ[seed] {
+        if !network.isSelfLoop(esucc) {
+            newLayer=append(newLayer,esucc)
+            inLayers[esucc]=true
+        }
+    }
+    for len(newLayer)!=0 {
+        for _,edge=range newLayer {
+            visited[network.edges[edge][1]]=true
+            layerPred[network.edges[edge][1]]=append(layerPred[network.edges[edge][1]],edge)
+        }
+        layer=newLayer
+        newLayer=[]int{}
+        for _,edge=range layer {
+            for _,esucc=range network.succ[network.edges[edge][1]] {
+                if !inLayers[esucc] && !visited[network.edges[esucc][1]] && !network.isSelfLoop(esucc) {
+                    newLayer=append(newLayer,esucc)
+                    inLayers[esucc]=true
+                }
+            }
+        }
+    }
+    return layerPred
+}
+func (network *Network) intersectEdges(edges1 []int,other *Network) []int {
+    var (
+        found bool
+        edge int
+        intersect []int
+    )
+    for _,edge=range edges1 {
+        _,found=other.edgeID(network.nodes[network.edges[edge][0]],network.nodes[network.edges[edge][1]])
+        if found {
+            intersect=append(intersect,edge)
+        }
+    }
+    return intersect
+}
+func (network *Network) isSelfLoop(edge int) bool {
+    return network.edges[edge][0]==network.edges[edge][1]
+}
+func (network *Network) isSelfLooped(node int) bool {
+    var (
+        found bool
+    )
+    _,found=network.edgeIDs[[2]int{node,node}]
+    return found
+}
+func (network *Network) shortestPaths(source,target int,layerPred [][]int) []int {
+    var (
+        found bool
+        edge,epred int
+        newCheck,toCheck,shortest []int
+        inShortest map[int]bool
+    )
+    inShortest=make(map[int]bool)
+    found=false
+    for _,epred=range layerPred[target] {
+        inShortest[epred]=true
+        shortest=append(shortest,epred)
+        newCheck=append(newCheck,epred)
+    }
+    for (len(newCheck)!=0) && !found {
+        for _,edge=range newCheck {
+            if network.edges[edge][0]==source {
+                found=true
+                break
+            }
+        }
+        if !found {
+            toCheck=newCheck
+            newCheck=[]int{}
+            for _,edge=range toCheck {
+                for _,epred=range layerPred[network.edges[edge][0]] {
+                    if !inShortest[epred] {
+                        inShortest[epred]=true
+                        shortest=append(shortest,epred)
+                        newCheck=append(newCheck,epred)
+                    }
+                }
+            }
+        }
+    }
+    if !found {
+        shortest=[]int{}
+    }
+    return shortest
+}
+func (network *Network) terminalNodes(nodeSP [][]int) []string {
+    var (
+        node int
+        termNodes []string
+    )
+    for node=range network.nodes {
+        if len(nodeSP[node])==0 {
+            termNodes=append(termNodes,network.nodes[node])
+        }
+    }
+    return termNodes
+}
@@ -0,0 +1,333 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "encoding/xml"
+    "errors"
+    "strconv"
+)
+// attributeKey is a node or edge attribute of a GraphML file: its name and its
+// type (attr.type, e.g. string or double).
+type attributeKey struct {
+    name,kind string
+}
+// graphmlFile and the following types hold the parts of a GraphML file used by
+// readGraphML and WriteGraphML.
+type graphmlFile struct {
+    XMLName xml.Name `xml:"graphml"`
+    Xmlns string `xml:"xmlns,attr,omitempty"`
+    Keys []graphmlKey `xml:"key"`
+    Graphs []graphmlGraph `xml:"graph"`
+}
+type graphmlKey struct {
+    ID string `xml:"id,attr"`
+    For string `xml:"for,attr"`
+    Name string `xml:"attr.name,attr"`
+    Type string `xml:"attr.type,attr"`
+    Default string `xml:"default,omitempty"`
+}
+type graphmlGraph struct {
+    ID string `xml:"id,attr,omitempty"`
+    EdgeDefault string `xml:"edgedefault,attr,omitempty"`
+    Nodes []graphmlNode `xml:"node"`
+    Edges []graphmlEdge `xml:"edge"`
+}
+type graphmlNode struct {
+    ID string `xml:"id,attr"`
+    Data []graphmlData `xml:"data"`
+}
+type graphmlEdge struct {
+    Source string `xml:"source,attr"`
+    Target string `xml:"target,attr"`
+    Directed string `xml:"directed,attr,omitempty"`
+    Data []graphmlData `xml:"data"`
+}
+type graphmlData struct {
+    Key string `xml:"key,attr"`
+    Value string `xml:",chardata"`
+}
+// readGraphML reads the first graph of a GraphML file into the network, the
+// node names being given by the node attribute name, or else label, or else by
+// the node IDs. The undirected edges (edgedefault="undirected" or
+// directed="false") are read as two edges, one per direction. The interaction
+// names are given by the edge attribute options.InteractionAttribute
+// (interaction by default) and, with options.Weighted, the weights by the edge
+// attribute weight. The other node and edge attributes are kept, the
+// attributes without name (e.g. the graphics of yEd) being ignored.
+func (network *Network) readGraphML(data []byte,options ReadOptions) error {
+    var (
+        err error
+        found,directed bool
+        i int
+        weight float64
+        name,source,target string
+        values map[string]string
+        names map[string]string
+        keys map[string]graphmlKey
+        file graphmlFile
+        key graphmlKey
+        node graphmlNode
+        edge graphmlEdge
+    )
+    err=xml.Unmarshal(data,&file)
+    if err!=nil {
+        err=errors.New("invalid GraphML: "+err.Error())
+    } else if len(file.Graphs)==0 {
+        err=errors.New("invalid GraphML: no graph")
+    } else {
+        if options.InteractionAttribute!="" {
+            network.interactionKey=options.InteractionAttribute
+        }
+        keys=make(map[string]graphmlKey)
+        for _,key=range file.Keys {
+            if key.Name!="" {
+                keys[key.ID]=key
+                if ((key.For=="node") || (key.For=="all")) && !hasKey(network.nodeKeys,key.Name) {
+                    network.nodeKeys=append(network.nodeKeys,attributeKey{name:key.Name,kind:key.Type})
+                }
+                if ((key.For=="edge") || (key.For=="all")) && (key.Name!=network.interactionName()) && !(options.Weighted && (key.Name=="weight")) && !hasKey(network.edgeKeys,key.Name) {
+                    network.edgeKeys=append(network.edgeKeys,attributeKey{name:key.Name,kind:key.Type})
+                }
+            }
+        }
+        names=make(map[string]string)
+        for _,node=range file.Graphs[0].Nodes {
+            values=graphmlValues(keys,"node",node.Data)
+            name=values["name"]
+            if name=="" {
+                name=values["label"]
+            }
+            if name=="" {
+                name=node.ID
+            }
+            names[node.ID]=name
+            i=network.addNode(name)
+            if (len(values)!=0) && (network.nodeAttributes[i]==nil) {
+                network.nodeAttributes[i]=values
+            }
+        }
+        for _,edge=range file.Graphs[0].Edges {
+            source,found=names[edge.Source]
+            if !found {
+                source=edge.Source
+            }
+            target,found=names[edge.Target]
+            if !found {
+                target=edge.Target
+            }
+            if edge.Directed=="" {
+                directed=file.Graphs[0].EdgeDefault!="undirected"
+            } else {
+                directed=(edge.Directed=="true") || (edge.Directed=="1")
+            }
+            values=graphmlValues(keys,"edge",edge.Data)
+            name,found=values[network.interactionName()]
+            delete(values,network.interactionName())
+            weight=1
+            if !found || (name=="") {
+                err=errors.New("edge "+source+" -> "+target+": missing attribute "+network.interactionName())
+            } else if options.Weighted {
+                weight,err=parseWeight(values["weight"])
+                delete(values,"weight")
+                if err!=nil {
+                    err=errors.New("edge "+source+" -> "+target+": "+err.Error())
+                }
+            }
+            if err!=nil {
+                break
+            } else {
+                network.addEdge(source,name,target,weight)
+                network.setEdgeAttributes(source,name,target,values)
+                if !directed {
+                    network.addEdge(target,name,source,weight)
+                    network.setEdgeAttributes(target,name,source,values)
+                }
+                network.weighted=network.weighted || options.Weighted
+            }
+        }
+    }
+    return err
+}
+// WriteGraphML writes the network to a GraphML file, one edge per interaction
+// name, along with the node and edge attributes read from GraphML files. The
+// interaction names are written in the edge attribute they were read from
+// (interaction by default) and, if the network is weighted, the weights in the
+// edge attribute weight, in place of the one read without ReadOptions.Weighted.
+func (network *Network) WriteGraphML(networkFile string) error {
+    var (
+        err error
+        found bool
+        i,node,edge int
+        name,value string
+        data []byte
+        edgeKeys []attributeKey
+        file graphmlFile
+        graph graphmlGraph
+        key attributeKey
+    )
+    if len(network.edges)==0 {
+        err=errors.New("empty before writing")
+    } else {
+        file.Xmlns="http://graphml.graphdrawing.org/xmlns"
+        for i,key=range network.nodeKeys {
+            file.Keys=append(file.Keys,graphmlKey{ID:"n"+strconv.Itoa(i),For:"node",Name:key.name,Type:key.kind})
+        }
+        edgeKeys=[]attributeKey{{name:network.interactionName(),kind:"string"}}
+        if network.weighted {
+            edgeKeys=append(edgeKeys,attributeKey{name:"weight",kind:"double"})
+        }
+        for _,key=range network.edgeKeys {
+            if !network.weighted || (key.name!="weight") {
+                edgeKeys=append(edgeKeys,key)
+            }
+        }
+        for i,key=range edgeKeys {
+            file.Keys=append(file.Keys,graphmlKey{ID:"e"+strconv.Itoa(i),For:"edge",Name:key.name,Type:key.kind})
+        }
+        graph=graphmlGraph{ID:"G",EdgeDefault:"directed"}
+        for node=range network.nodes {
+            graph.Nodes=append(graph.Nodes,graphmlNode{ID:network.nodes[node]})
+            for i,key=range network.nodeKeys {
+                value,found=network.nodeAttributes[node][key.name]
+                if found {
+                    graph.Nodes[node].Data=append(graph.Nodes[node].Data,graphmlData{Key:"n"+strconv.Itoa(i),Value:value})
+                }
+            }
+        }
+        for edge=range network.edges {
+            for i,name=range network.edgeNames[edge] {
+                graph.Edges=append(graph.Edges,network.graphmlEdge(edge,i,name,edgeKeys))
+            }
+        }
+        file.Graphs=[]graphmlGraph{graph}
+        data,err=xml.MarshalIndent(file,"","    ")
+        if err==nil {
+            err=writeFile(networkFile,append([]byte(xml.Header),append(data,'\n')...))
+        }
+    }
+    return err
+}
+// graphmlEdge returns the GraphML edge of the i-th interaction name of edge,
+// with its attributes in the order of keys.
+func (network *Network) graphmlEdge(edge,i int,name string,keys []attributeKey) graphmlEdge {
+    var (
+        found bool
+        j int
+        value string
+        key attributeKey
+        gEdge graphmlEdge
+    )
+    gEdge=graphmlEdge{Source:network.nodes[network.edges[edge][0]],Target:network.nodes[network.edges[edge][1]]}
+    for j,key=range keys {
+        if j==0 {
+            value,found=name,true
+        } else if network.weighted && (key.name=="weight") {
+            value,found=formatWeight(network.weights[edge][i]),true
+        } else {
+            value,found=network.edgeAttributes[edge][i][key.name]
+        }
+        if found {
+            gEdge.Data=append(gEdge.Data,graphmlData{Key:"e"+strconv.Itoa(j),Value:value})
+        }
+    }
+    return gEdge
+}
+// graphmlValues returns the named attribute values of a node or edge (for),
+// missing values taking the default value of their key, if any.
+func graphmlValues(keys map[string]graphmlKey,kind string,data []graphmlData) map[string]string {
+    var (
+        found bool
+        key graphmlKey
+        datum graphmlData
+        values map[string]string
+    )
+    values=make(map[string]string)
+    for _,key=range keys {
+        if ((key.For==kind) || (key.For=="all")) && (key.Default!="") {
+            values[key.Name]=key.Default
+        }
+    }
+    for _,datum=range data {
+        key,found=keys[datum.Key]
+        if found {
+            values[key.Name]=datum.Value
+        }
+    }
+    return values
+}
+// interactionName returns the attribute holding the interaction names in
+// GraphML files.
+func (network *Network) interactionName() string {
+    var (
+        name string
+    )
+    name=network.interactionKey
+    if name=="" {
+        name="interaction"
+    }
+    return name
+}
+// setEdgeAttributes gives attributes to the interaction name of the edge
+// source -> target, unless it already has some.
+func (network *Network) setEdgeAttributes(source,name,target string,values map[string]string) {
+    var (
+        edge,i int
+    )
+    edge=network.edgeIDs[[2]int{network.nodeIDs[source],network.nodeIDs[target]}]
+    for i=range network.edgeNames[edge] {
+        if (network.edgeNames[edge][i]==name) && (network.edgeAttributes[edge][i]==nil) && (len(values)!=0) {
+            network.edgeAttributes[edge][i]=values
+        }
+    }
+}
+// copyAttributes gives to the network the attributes of the i-th interaction
+// name of the given edge of other and of its nodes, along with their keys and
+// the mapping of other.
+func (network *Network) copyAttributes(other *Network,edge,i int) {
+    var (
+        key attributeKey
+    )
+    if network.interactionKey=="" {
+        network.interactionKey=other.interactionKey
+    }
+    if network.mapping==nil {
+        network.mapping=other.mapping
+    }
+    for _,key=range other.edgeKeys {
+        if !hasKey(network.edgeKeys,key.name) {
+            network.edgeKeys=append(network.edgeKeys,key)
+        }
+    }
+    network.setEdgeAttributes(other.nodes[other.edges[edge][0]],other.edgeNames[edge][i],other.nodes[other.edges[edge][1]],other.edgeAttributes[edge][i])
+    network.copyNodeAttributes(other,other.nodes[other.edges[edge][0]])
+    network.copyNodeAttributes(other,other.nodes[other.edges[edge][1]])
+}
+// copyNodeAttributes gives to node the attributes it has in other, along with
+// their keys, unless it already has some.
+func (network *Network) copyNodeAttributes(other *Network,node string) {
+    var (
+        key attributeKey
+    )
+    for _,key=range other.nodeKeys {
+        if !hasKey(network.nodeKeys,key.name) {
+            network.nodeKeys=append(network.nodeKeys,key)
+        }
+    }
+    if network.nodeAttributes[network.nodeIDs[node]]==nil {
+        network.nodeAttributes[network.nodeIDs[node]]=other.nodeAttributes[other.nodeIDs[node]]
+    }
+}
+func hasKey(keys []attributeKey,name string) bool {
+    var (
+        found bool
+        key attributeKey
+    )
+    for _,key=range keys {
+        if key.name==name {
+            found=true
+            break
+        }
+    }
+    return found
+}
@@ -0,0 +1,146 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+// testGraphML is a GraphML graph with node and edge attributes, a key without
+// name (d4) and weighted edges.
+const testGraphML=`<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+    <key id="d1" for="node" attr.name="type" attr.type="string"><default>protein</default></key>
+    <key id="d2" for="edge" attr.name="interaction" attr.type="string"/>
+    <key id="d3" for="edge" attr.name="weight" attr.type="double"/>
+    <key id="d4" for="node" attr.type="string"/>
+    <graph id="G" edgedefault="directed">
+        <node id="A"/>
+        <node id="B"><data key="d1">complex</data></node>
+        <node id="C"><data key="d4">ignored</data></node>
+        <edge source="A" target="B"><data key="d2">binding</data><data key="d3">2</data></edge>
+        <edge source="B" target="C"><data key="d2">activation</data><data key="d3">1.5</data></edge>
+        <edge source="C" target="A"><data key="d2">inhibition</data><data key="d3">1</data></edge>
+    </graph>
+</graphml>
+`
+// testUndirectedGraphML is an undirected GraphML graph with a directed edge
+// (B -> C) and nodes named after their name attribute (but n3).
+const testUndirectedGraphML=`<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+    <key id="d0" for="node" attr.name="name" attr.type="string"/>
+    <key id="d2" for="edge" attr.name="interaction" attr.type="string"/>
+    <key id="d3" for="edge" attr.name="weight" attr.type="double"/>
+    <graph id="G" edgedefault="undirected">
+        <node id="n0"><data key="d0">A</data></node>
+        <node id="n1"><data key="d0">B</data></node>
+        <node id="n2"><data key="d0">C</data></node>
+        <node id="n3"/>
+        <edge source="n0" target="n1"><data key="d2">binding</data><data key="d3">2</data></edge>
+        <edge source="n1" target="n2" directed="true"><data key="d2">activation</data><data key="d3">1.5</data></edge>
+        <edge source="n2" target="n3"><data key="d2">binding</data><data key="d3">1</data></edge>
+    </graph>
+</graphml>
+`
+func TestReadGraphML(t *testing.T) {
+    var (
+        err error
+        network *Network
+    )
+    network=readFixture(t,"network.graphml",testGraphML,ReadOptions{Weighted:true})
+    checkLists(t,"Read(GraphML)",sifLines(network),[]string{
+        "A binding B 2",
+        "B activation C 1.5",
+        "C inhibition A 1",
+    })
+    if (network.nodeAttributes[network.nodeIDs["A"]]["type"]!="protein") || (network.nodeAttributes[network.nodeIDs["B"]]["type"]!="complex") {
+        t.Errorf("Read(GraphML): got node attributes %v, want type protein by default",network.nodeAttributes)
+    }
+    if hasKey(network.nodeKeys,"") || hasKey(network.edgeKeys,"interaction") {
+        t.Errorf("Read(GraphML): got node keys %v and edge keys %v, want neither the unnamed key nor interaction",network.nodeKeys,network.edgeKeys)
+    }
+    checkLists(t,"Read(GraphML)",sifLines(readFixture(t,"network.graphml",testGraphML,ReadOptions{})),[]string{
+        "A binding B",
+        "B activation C",
+        "C inhibition A",
+    })
+    // undirected edges are read both ways
+    checkLists(t,"Read(GraphML)",sifLines(readFixture(t,"network.graphml",testUndirectedGraphML,ReadOptions{Weighted:true})),[]string{
+        "A binding B 2",
+        "B binding A 2",
+        "B activation C 1.5",
+        "C binding n3 1",
+        "n3 binding C 1",
+    })
+    _,err=Read(writeFixture(t,"network.graphml",strings.ReplaceAll(testUndirectedGraphML,`<data key="d2">activation</data>`,"")),ReadOptions{})
+    checkError(t,"Read(GraphML)",err,"edge B -> C: missing attribute interaction")
+    _,err=Read(writeFixture(t,"network.graphml",strings.ReplaceAll(testGraphML,`<data key="d2">activation</data>`,"")),ReadOptions{})
+    checkError(t,"Read(GraphML)",err,"edge B -> C: missing attribute interaction")
+    _,err=Read(writeFixture(t,"network.graphml",testGraphML),ReadOptions{InteractionAttribute:"label"})
+    checkError(t,"Read(GraphML)",err,"edge A -> B: missing attribute label")
+}
+func TestWriteGraphML(t *testing.T) {
+    var (
+        i int
+        err error
+        file string
+        content []byte
+        network,written *Network
+        tests []ReadOptions
+    )
+    tests=[]ReadOptions{{Weighted:true},{}}
+    for i=range tests {
+        network=readFixture(t,"network.graphml",testGraphML,tests[i])
+        file=filepath.Join(t.TempDir(),"out.graphml")
+        err=network.WriteGraphML(file)
+        if err!=nil {
+            t.Fatal(err)
+        }
+        written,err=Read(file,tests[i])
+        if err!=nil {
+            t.Fatal(err)
+        }
+        checkLists(t,"WriteGraphML",sifLines(written),sifLines(network))
+        if written.nodeAttributes[written.nodeIDs["B"]]["type"]!="complex" {
+            t.Errorf("WriteGraphML: got node attributes %v, want B of type complex",written.nodeAttributes)
+        }
+    }
+    // from a SIF file, without attributes
+    network=readFixture(t,"weighted.sif",testWeightedSIF,ReadOptions{Weighted:true})
+    err=network.WriteGraphML(file)
+    if err!=nil {
+        t.Fatal(err)
+    }
+    written,err=Read(file,ReadOptions{Weighted:true})
+    if err!=nil {
+        t.Fatal(err)
+    }
+    checkLists(t,"WriteGraphML",sifLines(written),sifLines(network))
+    // read unweighted then weighted, so that weight is declared once
+    network=readFixture(t,"network.graphml",testGraphML,ReadOptions{})
+    err=network.ReadWeights(writeFixture(t,"weights.tsv","A\tB\t3\n"))
+    if err!=nil {
+        t.Fatal(err)
+    }
+    err=network.WriteGraphML(file)
+    if err!=nil {
+        t.Fatal(err)
+    }
+    content,err=os.ReadFile(file)
+    if err!=nil {
+        t.Fatal(err)
+    }
+    if strings.Count(string(content),"attr.name=\"weight\"")!=1 {
+        t.Errorf("WriteGraphML: got %d weight keys, want 1",strings.Count(string(content),"attr.name=\"weight\""))
+    }
+    written,err=Read(file,ReadOptions{Weighted:true})
+    if err!=nil {
+        t.Fatal(err)
+    }
+    checkLists(t,"WriteGraphML",sifLines(written),[]string{"A binding B 3","B activation C 1","C inhibition A 1"})
+    if New().WriteGraphML(file)==nil {
+        t.Errorf("WriteGraphML: no error for an empty network")
+    }
+}
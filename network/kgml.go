@@ -0,0 +1,198 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "bufio"
+    "bytes"
+    "encoding/xml"
+    "errors"
+    "io"
+    "sort"
+    "strings"
+)
+// kgmlPathway and the following types hold the parts of a KGML file used by
+// readKGML.
+type kgmlPathway struct {
+    Entries []kgmlEntry `xml:"entry"`
+    Relations []kgmlRelation `xml:"relation"`
+}
+type kgmlEntry struct {
+    ID string `xml:"id,attr"`
+    Name string `xml:"name,attr"`
+    Type string `xml:"type,attr"`
+    Graphics []kgmlGraphics `xml:"graphics"`
+    Components []kgmlComponent `xml:"component"`
+}
+type kgmlGraphics struct {
+    Name string `xml:"name,attr"`
+}
+type kgmlComponent struct {
+    ID string `xml:"id,attr"`
+}
+type kgmlRelation struct {
+    Entry1 string `xml:"entry1,attr"`
+    Entry2 string `xml:"entry2,attr"`
+    Type string `xml:"type,attr"`
+    Subtypes []kgmlSubtype `xml:"subtype"`
+}
+type kgmlSubtype struct {
+    Name string `xml:"name,attr"`
+}
+// isXML tells whether the first non-blank character of a file is "<", namely
+// whether it is a KGML file rather than a SIF file, without consuming it. A
+// leading byte order mark is skipped.
+func isXML(reader *bufio.Reader) bool {
+    var (
+        found,done bool
+        i int
+        peeked []byte
+    )
+    for i=1;!done;i++ {
+        peeked,_=reader.Peek(i)
+        if len(peeked)<i {
+            done=true
+        } else if strings.IndexByte(" \t\r\n\xef\xbb\xbf",peeked[i-1])==-1 {
+            found=(peeked[i-1]=='<')
+            done=true
+        }
+    }
+    return found
+}
+// xmlRoot returns the name of the root element of an XML file, or "" if there
+// is none.
+func xmlRoot(data []byte) string {
+    var (
+        err error
+        isStart bool
+        root string
+        token xml.Token
+        start xml.StartElement
+        decoder *xml.Decoder
+    )
+    decoder=xml.NewDecoder(bytes.NewReader(data))
+    for (err==nil) && (root=="") {
+        token,err=decoder.Token()
+        start,isStart=token.(xml.StartElement)
+        if isStart {
+            root=start.Name.Local
+        }
+    }
+    return root
+}
+// readKGML reads a KGML file (KEGG pathway) into the network, as kgml2sif
+// does. Each KEGG ID of an entry is a node, named after the gene symbol given
+// by the graphics of the entries it comes first in, or after the KEGG ID
+// otherwise. A group entry is a complex named after its components sorted
+// and joined with "::", one complex per combination of their nodes, each
+// component being linked to the complex by a membership_CPXrel edge. Each
+// relation links all the nodes of its two entries, the interaction name
+// joining its subtypes suffixed by its type (e.g. activation_PPrel) with
+// commas, or being unknown suffixed by its type if it has no subtypes. The
+// entries of type map (links to other pathways) are ignored.
+func (network *Network) readKGML(file io.Reader) error {
+    var (
+        err error
+        id,symbol,node,name,member,source,target string
+        ids,nodes,names,complex []string
+        complexes [][]string
+        symbols map[string]string
+        entryNodes map[string][]string
+        pathway kgmlPathway
+        entry kgmlEntry
+        component kgmlComponent
+        relation kgmlRelation
+        subtype kgmlSubtype
+    )
+    err=xml.NewDecoder(file).Decode(&pathway)
+    if err!=nil {
+        err=errors.New("invalid KGML: "+err.Error())
+    } else {
+        symbols=make(map[string]string)
+        entryNodes=make(map[string][]string)
+        for _,entry=range pathway.Entries {
+            ids=strings.Fields(entry.Name)
+            if ((entry.Type=="gene") || (entry.Type=="ortholog")) && (len(ids)!=0) && (len(entry.Graphics)!=0) {
+                symbol=strings.TrimSpace(strings.Split(entry.Graphics[0].Name,",")[0])
+                symbol=strings.TrimSuffix(symbol,"...")
+                if (symbol!="") && (symbols[ids[0]]=="") {
+                    symbols[ids[0]]=symbol
+                }
+            }
+        }
+        for _,entry=range pathway.Entries {
+            if (entry.Type!="map") && (entry.Type!="group") {
+                nodes=[]string{}
+                for _,id=range strings.Fields(entry.Name) {
+                    node=id
+                    if symbols[id]!="" {
+                        node=symbols[id]
+                    }
+                    if !isInList(nodes,node) {
+                        nodes=append(nodes,node)
+                    }
+                }
+                entryNodes[entry.ID]=nodes
+            }
+        }
+        for _,entry=range pathway.Entries {
+            if entry.Type=="group" {
+                complexes=[][]string{{}}
+                for _,component=range entry.Components {
+                    complexes=combine(complexes,entryNodes[component.ID])
+                }
+                nodes=[]string{}
+                for _,complex=range complexes {
+                    if len(complex)!=0 {
+                        names=copyList(complex)
+                        sort.Strings(names)
+                        node=strings.Join(names,"::")
+                        for _,member=range complex {
+                            network.AddEdge(member,"membership_CPXrel",node)
+                        }
+                        if !isInList(nodes,node) {
+                            nodes=append(nodes,node)
+                        }
+                    }
+                }
+                entryNodes[entry.ID]=nodes
+            }
+        }
+        for _,relation=range pathway.Relations {
+            names=[]string{}
+            for _,subtype=range relation.Subtypes {
+                names=append(names,subtype.Name+"_"+relation.Type)
+            }
+            if len(names)==0 {
+                names=[]string{"unknown_"+relation.Type}
+            }
+            name=strings.Join(names,",")
+            for _,source=range entryNodes[relation.Entry1] {
+                for _,target=range entryNodes[relation.Entry2] {
+                    network.AddEdge(source,name,target)
+                }
+            }
+        }
+    }
+    return err
+}
+// combine extends each partial combination with each of nodes, or returns the
+// combinations unchanged if nodes is empty (e.g. a component of a group which
+// is not a node).
+func combine(combinations [][]string,nodes []string) [][]string {
+    var (
+        node string
+        combination []string
+        combined [][]string
+    )
+    if len(nodes)==0 {
+        combined=combinations
+    } else {
+        for _,combination=range combinations {
+            for _,node=range nodes {
+                combined=append(combined,append(copyList(combination),node))
+            }
+        }
+    }
+    return combined
+}
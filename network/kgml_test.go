@@ -0,0 +1,54 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "testing"
+)
+// testKGML is a KEGG pathway with a gene entry of two genes (ERBB2, ERBB3), a
+// group (EGFR::ERBB3), a compound and a link to another pathway.
+const testKGML=`<?xml version="1.0"?>
+<pathway name="path:hsa00000">
+    <entry id="1" name="hsa:1956" type="gene"><graphics name="EGFR, ERBB1"/></entry>
+    <entry id="2" name="hsa:2064 hsa:2065" type="gene"><graphics name="ERBB2..."/></entry>
+    <entry id="3" name="hsa:2065" type="gene"><graphics name="ERBB3"/></entry>
+    <entry id="4" name="undefined" type="group"><component id="1"/><component id="3"/></entry>
+    <entry id="5" name="path:hsa04010" type="map"><graphics name="MAPK signaling pathway"/></entry>
+    <entry id="6" name="cpd:C00076" type="compound"><graphics name="C00076"/></entry>
+    <relation entry1="1" entry2="2" type="PPrel"><subtype name="activation"/><subtype name="phosphorylation"/></relation>
+    <relation entry1="4" entry2="6" type="PCrel"/>
+    <relation entry1="1" entry2="5" type="maplink"><subtype name="compound"/></relation>
+</pathway>
+`
+func TestReadKGML(t *testing.T) {
+    checkLists(t,"Read(KGML)",sifLines(readFixture(t,"pathway.xml",testKGML,ReadOptions{})),[]string{
+        "EGFR membership_CPXrel EGFR::ERBB3",
+        "ERBB3 membership_CPXrel EGFR::ERBB3",
+        "EGFR activation_PPrel,phosphorylation_PPrel ERBB2",
+        "EGFR activation_PPrel,phosphorylation_PPrel ERBB3",
+        "EGFR::ERBB3 unknown_PCrel cpd:C00076",
+    })
+}
+func TestReadFiles(t *testing.T) {
+    var (
+        err error
+        network *Network
+    )
+    network,err=ReadFiles([]string{writeFixture(t,"pathway.xml",testKGML),writeFixture(t,"network.sif","ERBB2\tactivation\tAKT1\nEGFR\tactivation\tERBB2\n")},ReadOptions{})
+    checkError(t,"ReadFiles",err,"")
+    if err==nil {
+        checkLists(t,"ReadFiles",sifLines(network),[]string{
+            "EGFR membership_CPXrel EGFR::ERBB3",
+            "ERBB3 membership_CPXrel EGFR::ERBB3",
+            "EGFR activation_PPrel,phosphorylation_PPrel ERBB2",
+            "EGFR activation ERBB2",
+            "EGFR activation_PPrel,phosphorylation_PPrel ERBB3",
+            "EGFR::ERBB3 unknown_PCrel cpd:C00076",
+            "ERBB2 activation AKT1",
+        })
+    }
+    _,err=ReadFiles([]string{writeFixture(t,"pathway.xml",testKGML),writeFixture(t,"malformed.sif","A\tactivation\n")},ReadOptions{})
+    checkError(t,"ReadFiles",err,"malformed.sif: line 1: missing target")
+    _,err=ReadFiles([]string{writeFixture(t,"network.sif","A\n")},ReadOptions{})
+    checkError(t,"ReadFiles",err,"empty after reading")
+}
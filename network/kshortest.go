@@ -0,0 +1,168 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "strconv"
+    "strings"
+)
+// KShortestPaths returns, for each source/target pair, the k shortest simple
+// paths going from the source node to the target node (Yen's algorithm), the
+// length of a path being the sum of the weights of its edges if the network is
+// weighted. When a source node is also a target node, the paths going from it
+// to itself are its shortest simple cycles.
+func (network *Network) KShortestPaths(sources,targets []string,k int) []Path {
+    var (
+        source,target int
+        edgePath []int
+        paths []Path
+    )
+    for _,source=range network.nodeList(sources) {
+        for _,target=range network.nodeList(targets) {
+            for _,edgePath=range network.yen(source,target,k) {
+                paths=append(paths,network.toPath(edgePath))
+            }
+        }
+    }
+    return paths
+}
+// Subnetwork returns the network made of the edges of the given paths.
+func (network *Network) Subnetwork(paths []Path) *Network {
+    var (
+        found bool
+        i,edge int
+        edges []int
+        inEdges []bool
+        path Path
+    )
+    inEdges=make([]bool,len(network.edges))
+    for _,path=range paths {
+        for i=0;i<len(path.Names);i++ {
+            edge,found=network.edgeID(path.Nodes[i],path.Nodes[i+1])
+            if found && !inEdges[edge] {
+                inEdges[edge]=true
+                edges=append(edges,edge)
+            }
+        }
+    }
+    return network.sub(edges)
+}
+func (network *Network) yen(source,target,k int) [][]int {
+    var (
+        i,j,best,edge int
+        spur,total,prev []int
+        accepted,candidates [][]int
+        seen map[string]bool
+        removedNodes []bool
+        removedEdges map[int]bool
+    )
+    seen=make(map[string]bool)
+    spur=network.spurPath(source,target,make([]bool,len(network.nodes)),map[int]bool{})
+    if len(spur)!=0 {
+        accepted=append(accepted,spur)
+        seen[pathKey(spur)]=true
+    }
+    for (len(accepted)!=0) && (len(accepted)<k) {
+        prev=accepted[len(accepted)-1]
+        for i=range prev {
+            removedNodes=make([]bool,len(network.nodes))
+            removedEdges=make(map[int]bool)
+            for _,edge=range prev[:i] {
+                removedNodes[network.edges[edge][0]]=true
+            }
+            for j=range accepted {
+                if (len(accepted[j])>i) && intsEq(accepted[j][:i],prev[:i]) {
+                    removedEdges[accepted[j][i]]=true
+                }
+            }
+            spur=network.spurPath(network.edges[prev[i]][0],target,removedNodes,removedEdges)
+            if len(spur)!=0 {
+                total=append(copyInts(prev[:i]),spur...)
+                if !seen[pathKey(total)] {
+                    seen[pathKey(total)]=true
+                    candidates=append(candidates,total)
+                }
+            }
+        }
+        if len(candidates)==0 {
+            break
+        }
+        best=0
+        for i=range candidates {
+            if network.pathLength(candidates[i])<network.pathLength(candidates[best]) {
+                best=i
+            }
+        }
+        accepted=append(accepted,candidates[best])
+        candidates=append(candidates[:best],candidates[best+1:]...)
+    }
+    return accepted
+}
+// spurPath returns a shortest path going from one node to another with
+// dijkstraPath if the network is weighted, with bfsPath otherwise.
+func (network *Network) spurPath(from,to int,removedNodes []bool,removedEdges map[int]bool) []int {
+    var (
+        path []int
+    )
+    if network.weighted {
+        path=network.dijkstraPath(from,to,removedNodes,removedEdges)
+    } else {
+        path=network.bfsPath(from,to,removedNodes,removedEdges)
+    }
+    return path
+}
+// bfsPath returns a shortest path (as edge IDs) going from one node to
+// another, avoiding the removed nodes and edges, the target node being
+// reachable even if removed. If from = to, it returns a shortest cycle.
+func (network *Network) bfsPath(from,to int,removedNodes []bool,removedEdges map[int]bool) []int {
+    var (
+        found bool
+        node,edge,next int
+        path,toCheck,newCheck []int
+        parent []int
+        visited []bool
+    )
+    parent=make([]int,len(network.nodes))
+    visited=make([]bool,len(network.nodes))
+    visited[from]=(from!=to)
+    newCheck=[]int{from}
+    for (len(newCheck)!=0) && !found {
+        toCheck=newCheck
+        newCheck=[]int{}
+        for _,node=range toCheck {
+            for _,edge=range network.succ[node] {
+                next=network.edges[edge][1]
+                if !removedEdges[edge] && !visited[next] && ((next==to) || !removedNodes[next]) {
+                    visited[next]=true
+                    parent[next]=edge
+                    if next==to {
+                        found=true
+                        break
+                    }
+                    newCheck=append(newCheck,next)
+                }
+            }
+            if found {
+                break
+            }
+        }
+    }
+    if found {
+        node=to
+        for (len(path)==0) || (node!=from) {
+            path=append([]int{parent[node]},path...)
+            node=network.edges[parent[node]][0]
+        }
+    }
+    return path
+}
+func pathKey(path []int) string {
+    var (
+        edge int
+        key []string
+    )
+    for _,edge=range path {
+        key=append(key,strconv.Itoa(edge))
+    }
+    return strings.Join(key,",")
+}
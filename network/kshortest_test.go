@@ -0,0 +1,50 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "strings"
+    "testing"
+)
+func TestKShortestPaths(t *testing.T) {
+    var (
+        i int
+        network,shortcut,weighted *Network
+        tests []struct {
+            network *Network
+            sources,targets []string
+            k int
+            paths []string
+        }
+    )
+    network=readFixture(t,"network.sif",testSIF,ReadOptions{})
+    shortcut=readFixture(t,"shortcut.sif","A\tactivation\tB\nB\tactivation\tC\nA\tactivation\tC\nC\tactivation\tD\nA\tinhibition\tD\n",ReadOptions{})
+    weighted=readFixture(t,"weighted.sif",testWeightedSIF,ReadOptions{Weighted:true})
+    tests=[]struct {
+        network *Network
+        sources,targets []string
+        k int
+        paths []string
+    }{
+        {shortcut,[]string{"A"},[]string{"D"},3,[]string{"A D","A C D","A B C D"}},
+        // by increasing weight
+        {weighted,[]string{"A"},[]string{"D"},3,[]string{"A B C D","A C D","A D"}},
+        {weighted,[]string{"A"},[]string{"D"},1,[]string{"A B C D"}},
+        {shortcut,[]string{"A"},[]string{"D"},1,[]string{"A D"}},
+        {shortcut,[]string{"A"},[]string{"D"},10,[]string{"A D","A C D","A B C D"}},
+        {network,[]string{"A"},[]string{"F"},5,[]string{"A B C E F","A B D E F"}},
+        {network,[]string{"A"},[]string{"A"},2,[]string{"A B C A"}},
+        {network,[]string{"F"},[]string{"A"},2,[]string{}},
+    }
+    for i=range tests {
+        checkLists(t,"KShortestPaths("+strings.Join(tests[i].sources,",")+", "+strings.Join(tests[i].targets,",")+")",pathList(tests[i].network.KShortestPaths(tests[i].sources,tests[i].targets,tests[i].k)),tests[i].paths)
+    }
+}
+func TestSubnetwork(t *testing.T) {
+    var (
+        network *Network
+    )
+    network=readFixture(t,"network.sif",testSIF,ReadOptions{})
+    checkSets(t,"Subnetwork",edgeList(network.Subnetwork(network.KShortestPaths([]string{"X"},[]string{"E"},2))),[]string{"X A","A B","B C","C E","B D","D E"})
+    checkSets(t,"Subnetwork",edgeList(network.Subnetwork(nil)),[]string{})
+}
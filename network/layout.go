@@ -0,0 +1,394 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "math"
+    "sort"
+)
+// Dimensions of the layered drawings, in pixels, and number of sweeps of the
+// crossing reduction.
+const (
+    layoutNodeHeight=30.0
+    layoutLayerSpacing=80.0
+    layoutNodeGap=24.0
+    layoutMargin=20.0
+    layoutCharWidth=7.5
+    layoutSweeps=12
+)
+// layout is a layered drawing of a network, from top to bottom: the center
+// (x, y) and the width (w) of each node, and the route of each edge, namely
+// the points it goes through from its source to its target (nil for
+// self-loops), the reversed edges being shifted aside when the opposite edge
+// exists.
+type layout struct {
+    width,height float64
+    x,y,w []float64
+    routes [][][2]float64
+}
+// layered computes a layered (Sugiyama-style) drawing of the network, which
+// suits the source-to-target direction of the results:
+//     1. the cycles are broken by reversing the back edges of a depth-first
+//        search starting from the roots
+//     2. each node is put on the layer below its deepest predecessor (longest
+//        path layering), the edges spanning several layers going through one
+//        virtual node per crossed layer
+//     3. the crossings are reduced by ordering the nodes of each layer after
+//        the barycenter of their neighbours, sweeping the layers down and up
+//     4. the nodes are moved towards their neighbours while keeping their
+//        order and spacing
+func (network *Network) layered() *layout {
+    var (
+        opposite bool
+        edge,node,item,top,bottom,l,sweep,cross,bestCross int
+        back []bool
+        layer,chain []int
+        layers,best,up,down,chains [][]int
+        pos,x,width []float64
+        route [][2]float64
+        drawing *layout
+    )
+    back=network.backEdges()
+    layer=network.longestPathLayers(back)
+    for node=range network.nodes {
+        width=append(width,math.Max(40,layoutCharWidth*float64(len([]rune(network.nodes[node])))+20))
+        up=append(up,[]int{})
+        down=append(down,[]int{})
+    }
+    chains=make([][]int,len(network.edges))
+    for edge=range network.edges {
+        if !network.isSelfLoop(edge) {
+            top,bottom=network.edges[edge][0],network.edges[edge][1]
+            if back[edge] {
+                top,bottom=bottom,top
+            }
+            chain=[]int{top}
+            for l=layer[top]+1;l<layer[bottom];l++ {
+                layer=append(layer,l)
+                width=append(width,0)
+                up=append(up,[]int{})
+                down=append(down,[]int{})
+                chain=append(chain,len(layer)-1)
+            }
+            chain=append(chain,bottom)
+            for item=1;item<len(chain);item++ {
+                down[chain[item-1]]=append(down[chain[item-1]],chain[item])
+                up[chain[item]]=append(up[chain[item]],chain[item-1])
+            }
+            chains[edge]=chain
+        }
+    }
+    for item=range layer {
+        for len(layers)<=layer[item] {
+            layers=append(layers,[]int{})
+        }
+        layers[layer[item]]=append(layers[layer[item]],item)
+    }
+    pos=make([]float64,len(layer))
+    setPositions(layers,pos)
+    best=copyLayers(layers)
+    bestCross=crossings(layers,down,pos)
+    for sweep=0;(sweep<layoutSweeps) && (bestCross!=0);sweep++ {
+        if sweep%2==0 {
+            for l=1;l<len(layers);l++ {
+                orderLayer(layers[l],up,pos)
+            }
+        } else {
+            for l=len(layers)-2;l>=0;l-- {
+                orderLayer(layers[l],down,pos)
+            }
+        }
+        cross=crossings(layers,down,pos)
+        if cross<bestCross {
+            best=copyLayers(layers)
+            bestCross=cross
+        }
+    }
+    layers=best
+    setPositions(layers,pos)
+    x=placeLayers(layers,up,down,width)
+    drawing=&layout{
+        height:2*layoutMargin+layoutNodeHeight+float64(len(layers)-1)*layoutLayerSpacing,
+        x:x[:len(network.nodes)],
+        y:make([]float64,len(network.nodes)),
+        w:width[:len(network.nodes)],
+        routes:make([][][2]float64,len(network.edges)),
+    }
+    for item=range x {
+        drawing.width=math.Max(drawing.width,x[item]+width[item]/2+layoutMargin)
+    }
+    for node=range network.nodes {
+        drawing.y[node]=layerY(layer[node])
+    }
+    for edge=range network.edges {
+        chain=chains[edge]
+        if len(chain)!=0 {
+            route=[][2]float64{{x[chain[0]],layerY(layer[chain[0]])+layoutNodeHeight/2}}
+            for _,item=range chain[1:len(chain)-1] {
+                route=append(route,[2]float64{x[item],layerY(layer[item])})
+            }
+            route=append(route,[2]float64{x[chain[len(chain)-1]],layerY(layer[chain[len(chain)-1]])-layoutNodeHeight/2})
+            if back[edge] {
+                _,opposite=network.edgeIDs[[2]int{network.edges[edge][1],network.edges[edge][0]}]
+                if opposite {
+                    for item=range route {
+                        route[item][0]+=8
+                    }
+                }
+                for item=0;item<len(route)/2;item++ {
+                    route[item],route[len(route)-1-item]=route[len(route)-1-item],route[item]
+                }
+            }
+            drawing.routes[edge]=route
+        }
+    }
+    return drawing
+}
+// backEdges tells, for each edge, whether it is a back edge of a depth-first
+// search starting from the roots, namely whether reversing it breaks a cycle.
+// Self-loops are back edges.
+func (network *Network) backEdges() []bool {
+    var (
+        node int
+        state []int
+        back []bool
+    )
+    state=make([]int,len(network.nodes))
+    back=make([]bool,len(network.edges))
+    for _,node=range append(network.nodeList(network.Roots()),network.nodeList(network.nodes)...) {
+        if state[node]==0 {
+            network.markBackEdges(node,state,back)
+        }
+    }
+    return back
+}
+// markBackEdges performs the depth-first search of backEdges from node, state
+// being 0 for the unvisited nodes, 1 for the nodes being visited and 2 for the
+// visited ones.
+func (network *Network) markBackEdges(node int,state []int,back []bool) {
+    var (
+        edge,next int
+    )
+    state[node]=1
+    for _,edge=range network.succ[node] {
+        next=network.edges[edge][1]
+        if state[next]==0 {
+            network.markBackEdges(next,state,back)
+        } else if state[next]==1 {
+            back[edge]=true
+        }
+    }
+    state[node]=2
+}
+// longestPathLayers returns the layer of each node, the edges being reversed
+// if back, so that each node is on the layer below its deepest predecessor.
+func (network *Network) longestPathLayers(back []bool) []int {
+    var (
+        edge,node,top,bottom int
+        inDegrees,layer,toCheck []int
+        below [][]int
+    )
+    inDegrees=make([]int,len(network.nodes))
+    layer=make([]int,len(network.nodes))
+    below=make([][]int,len(network.nodes))
+    for edge=range network.edges {
+        if !network.isSelfLoop(edge) {
+            top,bottom=network.edges[edge][0],network.edges[edge][1]
+            if back[edge] {
+                top,bottom=bottom,top
+            }
+            below[top]=append(below[top],bottom)
+            inDegrees[bottom]+=1
+        }
+    }
+    for node=range network.nodes {
+        if inDegrees[node]==0 {
+            toCheck=append(toCheck,node)
+        }
+    }
+    for len(toCheck)!=0 {
+        node=toCheck[0]
+        toCheck=toCheck[1:]
+        for _,bottom=range below[node] {
+            if layer[node]+1>layer[bottom] {
+                layer[bottom]=layer[node]+1
+            }
+            inDegrees[bottom]-=1
+            if inDegrees[bottom]==0 {
+                toCheck=append(toCheck,bottom)
+            }
+        }
+    }
+    return layer
+}
+// orderLayer sorts the items of a layer after the barycenter of the positions
+// of their neighbours in the adjacent layer, the items without neighbours
+// keeping their position.
+func orderLayer(layer []int,neighbours [][]int,pos []float64) {
+    var (
+        item,neighbour,i int
+        barycenters map[int]float64
+    )
+    barycenters=make(map[int]float64)
+    for _,item=range layer {
+        barycenters[item]=pos[item]
+        if len(neighbours[item])!=0 {
+            barycenters[item]=0
+            for _,neighbour=range neighbours[item] {
+                barycenters[item]+=pos[neighbour]
+            }
+            barycenters[item]/=float64(len(neighbours[item]))
+        }
+    }
+    sort.SliceStable(layer,func(i,j int) bool {
+        return barycenters[layer[i]]<barycenters[layer[j]]
+    })
+    for i,item=range layer {
+        pos[item]=float64(i)
+    }
+}
+// crossings returns the number of edge crossings between the adjacent layers.
+// Sorting the edges between two layers by upper then lower end, two edges cross
+// if and only if their lower ends are inverted, so that the crossings are
+// counted as inversions in O(E log E) time.
+func crossings(layers,down [][]int,pos []float64) int {
+    var (
+        l,i,item,next,count int
+        pairs [][2]float64
+        lower []float64
+    )
+    for l=0;l<len(layers)-1;l++ {
+        pairs=[][2]float64{}
+        for _,item=range layers[l] {
+            for _,next=range down[item] {
+                pairs=append(pairs,[2]float64{pos[item],pos[next]})
+            }
+        }
+        sort.Slice(pairs,func(i,j int) bool {
+            return (pairs[i][0]<pairs[j][0]) || ((pairs[i][0]==pairs[j][0]) && (pairs[i][1]<pairs[j][1]))
+        })
+        lower=make([]float64,len(pairs))
+        for i=range pairs {
+            lower[i]=pairs[i][1]
+        }
+        count+=inversions(lower,make([]float64,len(lower)))
+    }
+    return count
+}
+// inversions sorts values by merge sort, using buffer of the same length, and
+// returns the number of pairs i<j such that values[i]>values[j].
+func inversions(values,buffer []float64) int {
+    var (
+        middle,i,j,k,count int
+    )
+    if len(values)>1 {
+        middle=len(values)/2
+        count=inversions(values[:middle],buffer[:middle])+inversions(values[middle:],buffer[middle:])
+        i,j=0,middle
+        for k=range buffer {
+            if (j==len(values)) || ((i<middle) && !(values[i]>values[j])) {
+                buffer[k]=values[i]
+                i+=1
+            } else {
+                buffer[k]=values[j]
+                count+=middle-i
+                j+=1
+            }
+        }
+        copy(values,buffer)
+    }
+    return count
+}
+// placeLayers returns the horizontal centers of the items, moving them towards
+// their neighbours, sweeping the layers down and up, while keeping their order
+// and a gap of layoutNodeGap between them.
+func placeLayers(layers,up,down [][]int,width []float64) []float64 {
+    var (
+        l,sweep,i,item,neighbour int
+        total float64
+        x,wanted,left,right []float64
+        neighbours [][]int
+    )
+    x=make([]float64,len(width))
+    for l=range layers {
+        total=0
+        for _,item=range layers[l] {
+            x[item]=total+width[item]/2
+            total+=width[item]+layoutNodeGap
+        }
+        for _,item=range layers[l] {
+            x[item]-=total/2
+        }
+    }
+    for sweep=0;sweep<4;sweep++ {
+        for l=range layers {
+            neighbours=up
+            if sweep%2==1 {
+                l=len(layers)-1-l
+                neighbours=down
+            }
+            wanted=make([]float64,len(layers[l]))
+            for i,item=range layers[l] {
+                wanted[i]=x[item]
+                if len(neighbours[item])!=0 {
+                    wanted[i]=0
+                    for _,neighbour=range neighbours[item] {
+                        wanted[i]+=x[neighbour]
+                    }
+                    wanted[i]/=float64(len(neighbours[item]))
+                }
+            }
+            left=copyFloats(wanted)
+            right=copyFloats(wanted)
+            for i=1;i<len(left);i++ {
+                left[i]=math.Max(left[i],left[i-1]+(width[layers[l][i-1]]+width[layers[l][i]])/2+layoutNodeGap)
+            }
+            for i=len(right)-2;i>=0;i-- {
+                right[i]=math.Min(right[i],right[i+1]-(width[layers[l][i]]+width[layers[l][i+1]])/2-layoutNodeGap)
+            }
+            for i,item=range layers[l] {
+                x[item]=(left[i]+right[i])/2
+            }
+        }
+    }
+    total=math.Inf(1)
+    for item=range x {
+        total=math.Min(total,x[item]-width[item]/2)
+    }
+    for item=range x {
+        x[item]+=layoutMargin-total
+    }
+    return x
+}
+func setPositions(layers [][]int,pos []float64) {
+    var (
+        l,i,item int
+    )
+    for l=range layers {
+        for i,item=range layers[l] {
+            pos[item]=float64(i)
+        }
+    }
+}
+func copyLayers(layers [][]int) [][]int {
+    var (
+        l int
+        y [][]int
+    )
+    y=make([][]int,len(layers))
+    for l=range layers {
+        y[l]=copyInts(layers[l])
+    }
+    return y
+}
+func copyFloats(list []float64) []float64 {
+    var (
+        y []float64
+    )
+    y=make([]float64,len(list))
+    copy(y,list)
+    return y
+}
+func layerY(l int) float64 {
+    return layoutMargin+layoutNodeHeight/2+float64(l)*layoutLayerSpacing
+}
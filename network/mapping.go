@@ -0,0 +1,212 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "encoding/csv"
+    "errors"
+    "os"
+    "strings"
+)
+// Mapping maps identifiers (e.g. Entrez IDs, UniProt accessions) to node names
+// taken from one namespace (e.g. HGNC symbols), as read by ReadMapping. It
+// records the ambiguous and unmapped identifiers it meets.
+type Mapping struct {
+    namespaces []string
+    names map[string][]string
+    isName map[string]bool
+    ambiguous,unmapped []string
+    met map[string]bool
+}
+// ReadMapping reads a TSV synonym table: a header naming the namespaces (one
+// per column), then one entity per line, several synonyms in a column being
+// separated by "|". Each synonym is mapped to the first synonym of the entity
+// in the given namespace (the first column if empty), or to its first synonym
+// in the first column if it has none in this namespace.
+func ReadMapping(mapFile,namespace string) (*Mapping,error) {
+    var (
+        err error
+        i,column int
+        name,synonym string
+        line []string
+        lines [][]string
+        file *os.File
+        reader *csv.Reader
+        mapping *Mapping
+    )
+    mapping=&Mapping{
+        names:make(map[string][]string),
+        isName:make(map[string]bool),
+        met:make(map[string]bool),
+    }
+    file,err=openFile(mapFile)
+    defer closeFile(file)
+    if err==nil {
+        reader=csv.NewReader(file)
+        reader.Comma='\t'
+        reader.Comment=0
+        reader.FieldsPerRecord=0
+        reader.LazyQuotes=false
+        reader.TrimLeadingSpace=false
+        reader.ReuseRecord=false
+        lines,err=reader.ReadAll()
+        if err==nil {
+            if len(lines)<2 {
+                err=errors.New("empty after reading")
+            } else {
+                mapping.namespaces=lines[0]
+                column=0
+                if namespace!="" {
+                    column=-1
+                    for i=range mapping.namespaces {
+                        if mapping.namespaces[i]==namespace {
+                            column=i
+                            break
+                        }
+                    }
+                    if column==-1 {
+                        err=errors.New(namespace+": unknown namespace, expecting one of: "+strings.Join(mapping.namespaces,", "))
+                    }
+                }
+            }
+            if err==nil {
+                for _,line=range lines[1:] {
+                    name=synonyms(line[column])[0]
+                    if name=="" {
+                        name=synonyms(line[0])[0]
+                    }
+                    if name!="" {
+                        mapping.isName[name]=true
+                        for i=range line {
+                            for _,synonym=range synonyms(line[i]) {
+                                if (synonym!="") && !isInList(mapping.names[synonym],name) {
+                                    mapping.names[synonym]=append(mapping.names[synonym],name)
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    }
+    return mapping,err
+}
+// Namespaces returns the namespaces of the mapping, as named in the header of
+// the mapping file.
+func (mapping *Mapping) Namespaces() []string {
+    return copyList(mapping.namespaces)
+}
+// Map returns the node name of an identifier. An identifier being a node name
+// is kept as is, and so are the ambiguous identifiers (mapped to several node
+// names) and the unmapped ones, which are recorded.
+func (mapping *Mapping) Map(id string) string {
+    var (
+        name string
+    )
+    name=id
+    if !mapping.isName[id] {
+        if len(mapping.names[id])==1 {
+            name=mapping.names[id][0]
+        } else if !mapping.met[id] {
+            mapping.met[id]=true
+            if len(mapping.names[id])==0 {
+                mapping.unmapped=append(mapping.unmapped,id)
+            } else {
+                mapping.ambiguous=append(mapping.ambiguous,id)
+            }
+        }
+    }
+    return name
+}
+// Ambiguous returns the ambiguous identifiers met by Map, in order of
+// appearance.
+func (mapping *Mapping) Ambiguous() []string {
+    return copyList(mapping.ambiguous)
+}
+// Unmapped returns the unmapped identifiers met by Map, in order of
+// appearance.
+func (mapping *Mapping) Unmapped() []string {
+    return copyList(mapping.unmapped)
+}
+// WriteReport writes a TSV file with the columns identifier, status
+// (ambiguous or unmapped) and names, listing the identifiers which Map kept as
+// is along with the node names of the ambiguous ones (separated by "|").
+func (mapping *Mapping) WriteReport(tableFile string) error {
+    var (
+        id string
+        lines []string
+    )
+    lines=append(lines,"identifier\tstatus\tnames")
+    for _,id=range mapping.ambiguous {
+        lines=append(lines,id+"\tambiguous\t"+strings.Join(mapping.names[id],"|"))
+    }
+    for _,id=range mapping.unmapped {
+        lines=append(lines,id+"\tunmapped\t")
+    }
+    return writeFile(tableFile,[]byte(strings.Join(lines,"\n")+"\n"))
+}
+// Map returns the network with its nodes renamed by mapping, the nodes mapped
+// to the same name being merged. The returned network also maps the
+// identifiers read by ReadNodes, ReadEdges and ReadWeights, as do the
+// networks derived from it.
+func (network *Network) Map(mapping *Mapping) *Network {
+    var (
+        node,edge,i,id int
+        newNetwork *Network
+    )
+    newNetwork=New()
+    newNetwork.mapping=mapping
+    newNetwork.weighted=network.weighted
+    newNetwork.confidence=network.confidence
+    newNetwork.interactionKey=network.interactionKey
+    newNetwork.nodeKeys=network.nodeKeys
+    newNetwork.edgeKeys=network.edgeKeys
+    for node=range network.nodes {
+        id=newNetwork.addNode(mapping.Map(network.nodes[node]))
+        if newNetwork.nodeAttributes[id]==nil {
+            newNetwork.nodeAttributes[id]=network.nodeAttributes[node]
+        }
+    }
+    for edge=range network.edges {
+        for i=range network.edgeNames[edge] {
+            newNetwork.addEdge(mapping.Map(network.nodes[network.edges[edge][0]]),network.edgeNames[edge][i],mapping.Map(network.nodes[network.edges[edge][1]]),network.weights[edge][i])
+            newNetwork.setEdgeAttributes(mapping.Map(network.nodes[network.edges[edge][0]]),network.edgeNames[edge][i],mapping.Map(network.nodes[network.edges[edge][1]]),network.edgeAttributes[edge][i])
+        }
+    }
+    return newNetwork
+}
+// mapName returns the node name of an identifier read from a file, through the
+// mapping of the network if any.
+func (network *Network) mapName(id string) string {
+    var (
+        name string
+    )
+    name=id
+    if network.mapping!=nil {
+        name=network.mapping.Map(id)
+    }
+    return name
+}
+// ambiguity returns, for an identifier which the mapping of the network finds
+// ambiguous, a note listing its node names, to be appended to error messages.
+func (network *Network) ambiguity(id string) string {
+    var (
+        note string
+    )
+    if (network.mapping!=nil) && !network.mapping.isName[id] && (len(network.mapping.names[id])>1) {
+        note=" (ambiguous identifier: "+strings.Join(network.mapping.names[id],", ")+")"
+    }
+    return note
+}
+// synonyms splits a field of a mapping file into its synonyms.
+func synonyms(field string) []string {
+    var (
+        i int
+        names []string
+    )
+    names=strings.Split(field,"|")
+    for i=range names {
+        names[i]=strings.TrimSpace(names[i])
+    }
+    return names
+}
@@ -0,0 +1,91 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "testing"
+)
+const testMapping="symbol\tentrez\tuniprot\n"+
+    "EGFR\t1956\tP00533|Q504U8\n"+
+    "ERBB2\t2064\tP04626\n"+
+    "ERBB3\t2065\tP21860|SHARED\n"+
+    "ERBB4\t2066\tQ15303|SHARED\n"
+func TestReadMapping(t *testing.T) {
+    var (
+        i int
+        err error
+        mapping *Mapping
+        tests []struct {
+            namespace string
+            ids,names []string
+        }
+    )
+    tests=[]struct {
+        namespace string
+        ids,names []string
+    }{
+        {"",[]string{"EGFR","1956","P00533","Q504U8","2064","SHARED","UNKNOWN"},[]string{"EGFR","EGFR","EGFR","EGFR","ERBB2","SHARED","UNKNOWN"}},
+        {"uniprot",[]string{"EGFR","1956","Q504U8","P00533","ERBB2"},[]string{"P00533","P00533","P00533","P00533","P04626"}},
+    }
+    for i=range tests {
+        mapping,err=ReadMapping(writeFixture(t,"mapping.tsv",testMapping),tests[i].namespace)
+        if err!=nil {
+            t.Fatalf("ReadMapping(%q): %v",tests[i].namespace,err)
+        }
+        checkLists(t,"Namespaces",mapping.Namespaces(),[]string{"symbol","entrez","uniprot"})
+        checkLists(t,"Map("+tests[i].namespace+")",mapNames(mapping,tests[i].ids),tests[i].names)
+    }
+    // an empty column falls back on the first one
+    mapping,err=ReadMapping(writeFixture(t,"mapping.tsv",testMapping+"NRG1\t\tQ02297\n"),"entrez")
+    if err!=nil {
+        t.Fatalf("ReadMapping(entrez): %v",err)
+    }
+    checkLists(t,"Map(entrez)",mapNames(mapping,[]string{"Q02297","NRG1","P00533"}),[]string{"NRG1","NRG1","1956"})
+    _,err=ReadMapping(writeFixture(t,"mapping.tsv",testMapping),"hgnc")
+    checkError(t,"ReadMapping",err,"hgnc: unknown namespace, expecting one of: symbol, entrez, uniprot")
+    _,err=ReadMapping(writeFixture(t,"mapping.tsv","symbol\tentrez\n"),"")
+    checkError(t,"ReadMapping",err,"empty after reading")
+}
+func TestMapReport(t *testing.T) {
+    var (
+        mapping *Mapping
+    )
+    mapping,_=ReadMapping(writeFixture(t,"mapping.tsv",testMapping),"")
+    mapNames(mapping,[]string{"SHARED","UNKNOWN","1956","SHARED","OTHER"})
+    checkLists(t,"Ambiguous",mapping.Ambiguous(),[]string{"SHARED"})
+    checkLists(t,"Unmapped",mapping.Unmapped(),[]string{"UNKNOWN","OTHER"})
+    checkFile(t,"WriteReport",mapping.WriteReport,"mapping.tsv",
+        "identifier\tstatus\tnames\n"+
+        "SHARED\tambiguous\tERBB3|ERBB4\n"+
+        "UNKNOWN\tunmapped\t\n"+
+        "OTHER\tunmapped\t\n",
+    )
+}
+func TestNetworkMap(t *testing.T) {
+    var (
+        err error
+        nodes []string
+        mapping *Mapping
+        network *Network
+    )
+    mapping,_=ReadMapping(writeFixture(t,"mapping.tsv",testMapping),"")
+    // 1956 and P00533 are both EGFR, so that their edges are merged
+    network=readFixture(t,"network.sif","1956\tactivation\t2064\nP00533\tactivation\tP04626\nP00533\tinhibition\tSHARED\n",ReadOptions{}).Map(mapping)
+    checkLists(t,"Map",network.Nodes(),[]string{"EGFR","ERBB2","SHARED"})
+    checkLists(t,"Map",sifLines(network),[]string{"EGFR activation ERBB2","EGFR inhibition SHARED"})
+    nodes,err=network.ReadNodes(writeFixture(t,"nodes.txt","P00533\n2064\nEGFR\n"))
+    checkError(t,"ReadNodes",err,"")
+    checkLists(t,"ReadNodes",nodes,[]string{"EGFR","ERBB2"})
+    _,err=network.ReadNodes(writeFixture(t,"nodes.txt","P21860\n"))
+    checkError(t,"ReadNodes",err,"P21860: node not in network")
+}
+func mapNames(mapping *Mapping,ids []string) []string {
+    var (
+        id string
+        names []string
+    )
+    for _,id=range ids {
+        names=append(names,mapping.Map(id))
+    }
+    return names
+}
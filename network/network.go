@@ -0,0 +1,684 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+
+// Package network is the graph engine of pathrider: it reads networks encoded
+// in the SIF, KGML or GraphML file formats, writes them in the SIF, GraphML,
+// cytoscape.js or CX2 file formats and finds paths of interest in them.
+// File names can be "-", standing for the standard input when reading and for
+// the standard output when writing.
+package network
+import (
+    "bufio"
+    "bytes"
+    "encoding/csv"
+    "errors"
+    "fmt"
+    "io"
+    "math"
+    "os"
+)
+// Errors returned by Connect when there are no connecting paths.
+var (
+    ErrNoForward=errors.New("no forward paths found")
+    ErrNoBackward=errors.New("no backward paths found")
+    ErrNoConnecting=errors.New("no connecting paths found")
+)
+// Network is a directed network whose edges can carry several interaction
+// names. The zero value is not usable, use New or Read instead.
+type Network struct {
+    nodes []string
+    nodeIDs map[string]int
+    edges [][2]int
+    edgeIDs map[[2]int]int
+    edgeNames [][]string
+    weights [][]float64
+    weighted,confidence bool
+    succ,pred [][]int
+    duplicates int
+    nodeKeys,edgeKeys []attributeKey
+    nodeAttributes []map[string]string
+    edgeAttributes [][]map[string]string
+    interactionKey string
+    mapping *Mapping
+}
+// New returns an empty network.
+func New() *Network {
+    var (
+        network *Network
+    )
+    network=&Network{
+        nodeIDs:make(map[string]int),
+        edgeIDs:make(map[[2]int]int),
+    }
+    return network
+}
+// ReadOptions tunes how Read parses network files.
+type ReadOptions struct {
+    // SkipComments makes Read ignore the lines starting with "#".
+    SkipComments bool
+    // Weighted makes Read take the last column of the lines as the weight of
+    // their edges ("source interaction target weight"), or the weight
+    // attribute of the edges of GraphML files.
+    Weighted bool
+    // InteractionAttribute is the edge attribute of GraphML files giving the
+    // interaction names (default: interaction).
+    InteractionAttribute string
+}
+// Read reads a network encoded in a SIF file, or in a KGML or GraphML file
+// (detected by its content), removing edge duplicates.
+func Read(networkFile string,options ReadOptions) (*Network,error) {
+    var (
+        err error
+        network *Network
+    )
+    network=New()
+    err=network.readFile(networkFile,options)
+    if (err==nil) && (len(network.edges)==0) {
+        err=errors.New("empty after reading")
+    }
+    return network,err
+}
+// ReadFiles is like Read but merges several network files, possibly mixing SIF,
+// KGML and GraphML files, into one network.
+func ReadFiles(networkFiles []string,options ReadOptions) (*Network,error) {
+    var (
+        err error
+        networkFile string
+        network *Network
+    )
+    network=New()
+    for _,networkFile=range networkFiles {
+        err=network.readFile(networkFile,options)
+        if err!=nil {
+            if len(networkFiles)>1 {
+                err=fmt.Errorf("%s: %w",networkFile,err)
+            }
+            break
+        }
+    }
+    if (err==nil) && (len(network.edges)==0) {
+        err=errors.New("empty after reading")
+    }
+    return network,err
+}
+// AddEdge adds the edge source -> target with the given interaction name,
+// ignoring duplicates.
+func (network *Network) AddEdge(source,name,target string) {
+    network.addEdge(source,name,target,1)
+}
+// AddWeightedEdge is like AddEdge but also gives a weight to the interaction,
+// making the network weighted. Interactions added without weight weigh 1.
+func (network *Network) AddWeightedEdge(source,name,target string,weight float64) {
+    network.addEdge(source,name,target,weight)
+    network.weighted=true
+}
+func (network *Network) addEdge(source,name,target string,weight float64) {
+    var (
+        found bool
+        edge int
+        nodes [2]int
+    )
+    nodes=[2]int{network.addNode(source),network.addNode(target)}
+    edge,found=network.edgeIDs[nodes]
+    if !found {
+        edge=len(network.edges)
+        network.edges=append(network.edges,nodes)
+        network.edgeIDs[nodes]=edge
+        network.edgeNames=append(network.edgeNames,[]string{})
+        network.weights=append(network.weights,[]float64{})
+        network.edgeAttributes=append(network.edgeAttributes,[]map[string]string{})
+        network.succ[nodes[0]]=append(network.succ[nodes[0]],edge)
+        network.pred[nodes[1]]=append(network.pred[nodes[1]],edge)
+    }
+    if !isInList(network.edgeNames[edge],name) {
+        network.edgeNames[edge]=append(network.edgeNames[edge],name)
+        network.weights[edge]=append(network.weights[edge],weight)
+        network.edgeAttributes[edge]=append(network.edgeAttributes[edge],nil)
+    } else {
+        network.duplicates+=1
+    }
+}
+// AddNode adds node to the network, ignoring duplicates.
+func (network *Network) AddNode(node string) {
+    network.addNode(node)
+}
+// Nodes returns the nodes of the network, in order of appearance.
+func (network *Network) Nodes() []string {
+    return copyList(network.nodes)
+}
+// Edges returns the edges of the network as source/target pairs, in order of
+// appearance.
+func (network *Network) Edges() [][]string {
+    var (
+        edge int
+        edges [][]string
+    )
+    edges=make([][]string,len(network.edges))
+    for edge=range network.edges {
+        edges[edge]=[]string{network.nodes[network.edges[edge][0]],network.nodes[network.edges[edge][1]]}
+    }
+    return edges
+}
+// EdgeNames returns the interaction names of the edge source -> target.
+func (network *Network) EdgeNames(source,target string) []string {
+    var (
+        found bool
+        edge int
+        names []string
+    )
+    edge,found=network.edgeID(source,target)
+    if found {
+        names=copyList(network.edgeNames[edge])
+    }
+    return names
+}
+// HasNode reports whether node is in the network.
+func (network *Network) HasNode(node string) bool {
+    var (
+        found bool
+    )
+    _,found=network.nodeIDs[node]
+    return found
+}
+// HasEdge reports whether the edge source -> target is in the network.
+func (network *Network) HasEdge(source,target string) bool {
+    var (
+        found bool
+    )
+    _,found=network.edgeID(source,target)
+    return found
+}
+// NumNodes returns the number of nodes in the network.
+func (network *Network) NumNodes() int {
+    return len(network.nodes)
+}
+// NumEdges returns the number of edges in the network.
+func (network *Network) NumEdges() int {
+    return len(network.edges)
+}
+// ReadNodes reads a list of nodes (one node per line), each of them having to
+// be in the network once mapped (see Map).
+func (network *Network) ReadNodes(nodeFile string) ([]string,error) {
+    var (
+        err error
+        node string
+        line,nodes []string
+        lines [][]string
+        inNodes map[string]bool
+        file *os.File
+        reader *csv.Reader
+    )
+    inNodes=make(map[string]bool)
+    file,err=openFile(nodeFile)
+    defer closeFile(file)
+    if err==nil {
+        reader=csv.NewReader(file)
+        reader.Comma='\t'
+        reader.Comment=0
+        reader.FieldsPerRecord=1
+        reader.LazyQuotes=false
+        reader.TrimLeadingSpace=true
+        reader.ReuseRecord=true
+        lines,err=reader.ReadAll()
+        if err==nil {
+            for _,line=range lines {
+                node=network.mapName(line[0])
+                if !network.HasNode(node) {
+                    err=errors.New(line[0]+": node not in network"+network.ambiguity(line[0]))
+                    break
+                } else if !inNodes[node] {
+                    inNodes[node]=true
+                    nodes=append(nodes,node)
+                }
+            }
+            if err==nil {
+                if len(nodes)==0 {
+                    err=errors.New("empty after reading")
+                }
+            }
+        }
+    }
+    return nodes,err
+}
+// Blacklist returns the network without the given nodes, namely without the
+// edges involving them.
+func (network *Network) Blacklist(blackNodes []string) (*Network,error) {
+    var (
+        err error
+        edge int
+        edges []int
+        black []bool
+        newNetwork *Network
+    )
+    black=network.nodeSet(blackNodes)
+    for edge=range network.edges {
+        if !black[network.edges[edge][0]] && !black[network.edges[edge][1]] {
+            edges=append(edges,edge)
+        }
+    }
+    newNetwork=network.sub(edges)
+    if len(newNetwork.edges)==0 {
+        err=errors.New("network empty after blacklisting")
+    }
+    return newNetwork,err
+}
+// ReadEdges reads a list of edges of the network, either as SIF lines (source
+// interaction target1 target2 ...) or as source/target pairs (source target),
+// the latter standing for all the interaction names of the edge, the nodes
+// being mapped (see Map). It returns the edges as (source, interaction,
+// target) triples, the interaction being empty for source/target pairs.
+func (network *Network) ReadEdges(edgeFile string) ([][]string,error) {
+    var (
+        err error
+        found bool
+        i,edge int
+        key string
+        line,triple []string
+        lines,edges [][]string
+        inEdges map[string]bool
+        file *os.File
+        reader *csv.Reader
+    )
+    inEdges=make(map[string]bool)
+    file,err=openFile(edgeFile)
+    defer closeFile(file)
+    if err==nil {
+        reader=csv.NewReader(file)
+        reader.Comma='\t'
+        reader.Comment=0
+        reader.FieldsPerRecord=-1
+        reader.LazyQuotes=false
+        reader.TrimLeadingSpace=true
+        reader.ReuseRecord=false
+        lines,err=reader.ReadAll()
+        if err==nil {
+            for _,line=range lines {
+                for i=range line {
+                    if (i!=1) || (len(line)==2) {
+                        line[i]=network.mapName(line[i])
+                    }
+                }
+                triple=[]string{}
+                if len(line)==2 {
+                    edge,found=network.edgeID(line[0],line[1])
+                    if !found {
+                        err=errors.New(line[0]+" -> "+line[1]+": edge not in network")
+                        break
+                    }
+                    triple=append(triple,line[0],"",line[1])
+                } else if len(line)>=3 {
+                    for i=2;i<len(line);i++ {
+                        edge,found=network.edgeID(line[0],line[i])
+                        if !found || !isInList(network.edgeNames[edge],line[1]) {
+                            err=errors.New(line[0]+" "+line[1]+" "+line[i]+": edge not in network")
+                            break
+                        }
+                        triple=append(triple,line[0],line[1],line[i])
+                    }
+                    if err!=nil {
+                        break
+                    }
+                } else {
+                    err=errors.New(line[0]+": expecting source target or source interaction target")
+                    break
+                }
+                for i=0;i<len(triple);i+=3 {
+                    key=triple[i]+"\t"+triple[i+1]+"\t"+triple[i+2]
+                    if !inEdges[key] {
+                        inEdges[key]=true
+                        edges=append(edges,copyList(triple[i:i+3]))
+                    }
+                }
+            }
+            if err==nil {
+                if len(edges)==0 {
+                    err=errors.New("empty after reading")
+                }
+            }
+        }
+    }
+    return edges,err
+}
+// BlacklistEdges returns the network without the given (source, interaction,
+// target) edges, as returned by ReadEdges. An empty interaction removes the
+// whole edge, otherwise only this interaction name is removed, the edge being
+// removed along with its last interaction name.
+func (network *Network) BlacklistEdges(blackEdges [][]string) (*Network,error) {
+    var (
+        err error
+        found bool
+        i,edge int
+        name string
+        blackEdge []string
+        blackNames []map[string]bool
+        blackAll []bool
+        newNetwork *Network
+    )
+    blackNames=make([]map[string]bool,len(network.edges))
+    blackAll=make([]bool,len(network.edges))
+    for _,blackEdge=range blackEdges {
+        edge,found=network.edgeID(blackEdge[0],blackEdge[2])
+        if found {
+            if blackEdge[1]=="" {
+                blackAll[edge]=true
+            } else {
+                if blackNames[edge]==nil {
+                    blackNames[edge]=make(map[string]bool)
+                }
+                blackNames[edge][blackEdge[1]]=true
+            }
+        }
+    }
+    newNetwork=New()
+    for edge=range network.edges {
+        if !blackAll[edge] {
+            for i,name=range network.edgeNames[edge] {
+                if !blackNames[edge][name] {
+                    newNetwork.copyName(network,edge,i)
+                }
+            }
+        }
+    }
+    if len(newNetwork.edges)==0 {
+        err=errors.New("network empty after blacklisting")
+    }
+    return newNetwork,err
+}
+// Forward returns the downstream paths starting from the seed nodes, up to the
+// given depth (NaN for no maximal depth).
+func (network *Network) Forward(seeds []string,depth float64) *Network {
+    return network.sub(network.forwardEdges(network.nodeList(seeds),depth))
+}
+// Backward returns the upstream paths starting from the seed nodes, up to the
+// given depth (NaN for no maximal depth).
+func (network *Network) Backward(seeds []string,depth float64) *Network {
+    return network.sub(network.backwardEdges(network.nodeList(seeds),depth))
+}
+// Intersect returns the edges of the network which are also in other.
+func (network *Network) Intersect(other *Network) *Network {
+    var (
+        edge int
+        edges []int
+    )
+    edges=make([]int,len(network.edges))
+    for edge=range network.edges {
+        edges[edge]=edge
+    }
+    return network.sub(network.intersectEdges(edges,other))
+}
+// Connect returns the paths connecting the source nodes to the target nodes.
+// If there are none, the returned network is empty and the error is one of
+// ErrNoForward, ErrNoBackward or ErrNoConnecting.
+func (network *Network) Connect(sources,targets []string) (*Network,error) {
+    var (
+        err error
+        forward,backward,intersect *Network
+    )
+    forward=network.Forward(sources,math.NaN())
+    backward=network.Backward(targets,math.NaN())
+    if len(forward.edges)==0 {
+        intersect,err=New(),ErrNoForward
+    } else if len(backward.edges)==0 {
+        intersect,err=New(),ErrNoBackward
+    } else {
+        intersect=forward.Intersect(backward)
+        if len(intersect.edges)==0 {
+            err=ErrNoConnecting
+        }
+    }
+    return intersect,err
+}
+// Via returns the connecting paths passing through waypoints: with all, the
+// paths going from the source nodes to the target nodes through every waypoint
+// in the given order, otherwise the paths going through at least one of them.
+// It is typically applied to the result of Connect.
+func (network *Network) Via(sources,targets,waypoints []string,all bool) *Network {
+    var (
+        valid bool
+        i,waypoint,edge int
+        stops [][]int
+        legs,nextLeg,edges []int
+        inEdges []bool
+    )
+    inEdges=make([]bool,len(network.edges))
+    if all {
+        stops=append(stops,network.nodeList(sources))
+        for _,waypoint=range network.nodeList(waypoints) {
+            stops=append(stops,[]int{waypoint})
+        }
+        stops=append(stops,network.nodeList(targets))
+        valid=true
+        for i=0;i+1<len(stops);i++ {
+            edges=network.connectEdges(stops[i],stops[i+1])
+            if (len(edges)==0) && !intsShare(stops[i],stops[i+1]) {
+                valid=false
+                break
+            }
+            legs=append(legs,edges...)
+        }
+        edges=[]int{}
+        if valid {
+            for _,edge=range legs {
+                if !inEdges[edge] {
+                    inEdges[edge]=true
+                    edges=append(edges,edge)
+                }
+            }
+        }
+    } else {
+        for _,waypoint=range network.nodeList(waypoints) {
+            stops=[][]int{network.nodeList(sources),{waypoint},network.nodeList(targets)}
+            legs=network.connectEdges(stops[0],stops[1])
+            nextLeg=network.connectEdges(stops[1],stops[2])
+            valid=((len(legs)!=0) || intsShare(stops[0],stops[1])) && ((len(nextLeg)!=0) || intsShare(stops[1],stops[2]))
+            legs=append(legs,nextLeg...)
+            if valid {
+                for _,edge=range legs {
+                    if !inEdges[edge] {
+                        inEdges[edge]=true
+                        edges=append(edges,edge)
+                    }
+                }
+            }
+        }
+    }
+    return network.sub(edges)
+}
+// Within returns the edges lying on at least one walk going from a source node
+// to a target node with at most maxLength edges, namely the edges u -> v such
+// that the distance from the source nodes to u plus 1 plus the distance from v
+// to the target nodes is at most maxLength. The bound is on walks, which can
+// visit a node twice: with A -> B -> D and B -> A, B -> A is kept from A to D
+// with maxLength 4 (A B A B D) although no simple path goes through it. It is
+// typically applied to the result of Connect.
+func (network *Network) Within(sources,targets []string,maxLength float64) *Network {
+    var (
+        edge int
+        edges []int
+        fromSources,toTargets []float64
+    )
+    fromSources=network.distancesFrom(network.nodeList(sources))
+    toTargets=network.distancesTo(network.nodeList(targets))
+    for edge=range network.edges {
+        if fromSources[network.edges[edge][0]]+1+toTargets[network.edges[edge][1]]<=maxLength {
+            edges=append(edges,edge)
+        }
+    }
+    return network.sub(edges)
+}
+// Shortest returns the shortest paths connecting the source nodes to the
+// target nodes, the length of a path being the sum of the weights of its edges
+// if the network is weighted (Dijkstra's algorithm), its number of edges
+// otherwise. It is typically applied to the result of Connect.
+func (network *Network) Shortest(sources,targets []string) *Network {
+    return network.sub(network.allShortestPaths(network.nodeList(sources),network.nodeList(targets)))
+}
+// Stream returns the upstream (direction "up"), downstream (direction "down")
+// or both upstream and downstream (direction "both") paths starting from the
+// seed nodes, up to the given depth (NaN for no maximal depth).
+func (network *Network) Stream(seeds []string,direction string,depth float64) (*Network,error) {
+    var (
+        err error
+        edge int
+        edges []int
+        inEdges []bool
+        ward *Network
+    )
+    if direction=="up" {
+        ward=network.Backward(seeds,depth)
+    } else if direction=="down" {
+        ward=network.Forward(seeds,depth)
+    } else if direction=="both" {
+        inEdges=make([]bool,len(network.edges))
+        for _,edge=range append(network.backwardEdges(network.nodeList(seeds),depth),network.forwardEdges(network.nodeList(seeds),depth)...) {
+            if !inEdges[edge] {
+                inEdges[edge]=true
+                edges=append(edges,edge)
+            }
+        }
+        ward=network.sub(edges)
+    } else {
+        ward,err=New(),errors.New(direction+": unknown direction, expecting one of: up, down, both")
+    }
+    return ward,err
+}
+// Neighbours returns the nodes adjacent to the given nodes, excluding them.
+func (network *Network) Neighbours(nodes []string) []string {
+    var (
+        node,edge int
+        neighbours []string
+        given,adjacent []bool
+    )
+    given=network.nodeSet(nodes)
+    adjacent=make([]bool,len(network.nodes))
+    for _,node=range network.nodeList(nodes) {
+        for _,edge=range network.succ[node] {
+            adjacent[network.edges[edge][1]]=true
+        }
+        for _,edge=range network.pred[node] {
+            adjacent[network.edges[edge][0]]=true
+        }
+    }
+    for node=range network.nodes {
+        if adjacent[node] && !given[node] {
+            neighbours=append(neighbours,network.nodes[node])
+        }
+    }
+    return neighbours
+}
+// Roots returns the nodes having no predecessors.
+func (network *Network) Roots() []string {
+    return network.terminalNodes(network.pred)
+}
+// Leaves returns the nodes having no successors.
+func (network *Network) Leaves() []string {
+    return network.terminalNodes(network.succ)
+}
+func (network *Network) addNode(node string) int {
+    var (
+        found bool
+        id int
+    )
+    id,found=network.nodeIDs[node]
+    if !found {
+        id=len(network.nodes)
+        network.nodes=append(network.nodes,node)
+        network.nodeIDs[node]=id
+        network.succ=append(network.succ,[]int{})
+        network.pred=append(network.pred,[]int{})
+        network.nodeAttributes=append(network.nodeAttributes,nil)
+    }
+    return id
+}
+func (network *Network) edgeID(source,target string) (int,bool) {
+    var (
+        found bool
+        edge int
+        nodes [2]int
+    )
+    nodes[0],found=network.nodeIDs[source]
+    if found {
+        nodes[1],found=network.nodeIDs[target]
+        if found {
+            edge,found=network.edgeIDs[nodes]
+        }
+    }
+    return edge,found
+}
+// nodeList returns the IDs of the given nodes, ignoring those which are not in
+// the network.
+func (network *Network) nodeList(nodes []string) []int {
+    var (
+        found bool
+        id int
+        node string
+        ids []int
+    )
+    for _,node=range nodes {
+        id,found=network.nodeIDs[node]
+        if found {
+            ids=append(ids,id)
+        }
+    }
+    return ids
+}
+// nodeSet returns the given nodes as a boolean slice indexed by node ID.
+func (network *Network) nodeSet(nodes []string) []bool {
+    var (
+        id int
+        set []bool
+    )
+    set=make([]bool,len(network.nodes))
+    for _,id=range network.nodeList(nodes) {
+        set[id]=true
+    }
+    return set
+}
+func (network *Network) sub(edges []int) *Network {
+    var (
+        i,edge int
+        newNetwork *Network
+    )
+    newNetwork=New()
+    for _,edge=range edges {
+        for i=range network.edgeNames[edge] {
+            newNetwork.copyName(network,edge,i)
+        }
+    }
+    return newNetwork
+}
+// readFile reads a SIF, KGML or GraphML file into the network, XML files being
+// told apart by their root element.
+func (network *Network) readFile(networkFile string,options ReadOptions) error {
+    var (
+        err error
+        data []byte
+        file *os.File
+        reader *bufio.Reader
+    )
+    file,err=openFile(networkFile)
+    defer closeFile(file)
+    if err==nil {
+        reader=bufio.NewReader(file)
+        if !isXML(reader) {
+            err=network.readSIF(reader,options)
+        } else {
+            data,err=io.ReadAll(reader)
+            if (err==nil) && (xmlRoot(data)=="graphml") {
+                err=network.readGraphML(data,options)
+            } else if err==nil {
+                err=network.readKGML(bytes.NewReader(data))
+            }
+        }
+    }
+    return err
+}
+// copyName adds to the network the i-th interaction name of the given edge of
+// other, along with its weight and its attributes.
+func (network *Network) copyName(other *Network,edge,i int) {
+    network.addEdge(other.nodes[other.edges[edge][0]],other.edgeNames[edge][i],other.nodes[other.edges[edge][1]],other.weights[edge][i])
+    network.weighted=network.weighted || other.weighted
+    network.confidence=network.confidence || other.confidence
+    network.copyAttributes(other,edge,i)
+}
@@ -0,0 +1,836 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "errors"
+    "math"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+    "testing"
+)
+// testSIF is the network of most tests: A, B and C form a cycle, reached from
+// X and leading to F through D or E.
+const testSIF="A\tactivation\tB\n"+
+    "B\tactivation\tC\n"+
+    "C\tinhibition\tA\n"+
+    "B\tactivation\tD\n"+
+    "D\tactivation\tE\n"+
+    "C\tactivation\tE\n"+
+    "E\tactivation\tF\n"+
+    "X\tactivation\tA\n"
+// testWeightedSIF is a weighted network where A reaches D directly (weight 5),
+// through C (weight 4) or through B and C (weight 3).
+const testWeightedSIF="A\tactivation\tB\t1\n"+
+    "B\tactivation\tC\t1\n"+
+    "A\tactivation\tC\t3\n"+
+    "C\tactivation\tD\t1\n"+
+    "A\tinhibition\tD\t5\n"
+// testExamples lists the examples of the readme: the network, source and
+// target files of pathrider connect, or the network and seed files and the
+// direction of pathrider stream.
+type testExample struct {
+    name,dir,networkFile,sourceFile,targetFile,direction string
+}
+var testExamples=[]testExample{
+    {"Cell_cycle","../examples/pathrider-connect/Cell_cycle","Cell_cycle.sif","nodes.txt","nodes.txt",""},
+    {"Cell_survival","../examples/pathrider-connect/Cell_survival","Cell_survival.sif","nodes.txt","nodes.txt",""},
+    {"ErbB_signaling_pathway","../examples/pathrider-connect/ErbB_signaling_pathway","ErbB_signaling_pathway.sif","sources.txt","targets.txt",""},
+    {"Insulin_signaling_pathway","../examples/pathrider-connect/Insulin_signaling_pathway","Insulin_signaling_pathway.sif","sources.txt","targets.txt",""},
+    {"ErbB_signaling_pathway_up","../examples/pathrider-stream/ErbB_signaling_pathway","ErbB_signaling_pathway.sif","seeds.txt","","up"},
+    {"Toll-like_receptor_signaling_pathway_down","../examples/pathrider-stream/Toll-like_receptor_signaling_pathway","Toll-like_receptor_signaling_pathway.sif","seeds.txt","","down"},
+}
+func TestForward(t *testing.T) {
+    var (
+        i int
+        network *Network
+        tests []struct {
+            seeds []string
+            depth float64
+            edges []string
+        }
+    )
+    network=readFixture(t,"network.sif",testSIF,ReadOptions{})
+    tests=[]struct {
+        seeds []string
+        depth float64
+        edges []string
+    }{
+        {[]string{"A"},math.NaN(),[]string{"A B","B C","B D","C A","C E","D E","E F"}},
+        {[]string{"A"},2,[]string{"A B","B C","B D"}},
+        {[]string{"X"},1,[]string{"X A"}},
+        {[]string{"D","X"},1,[]string{"D E","X A"}},
+        {[]string{"F"},math.NaN(),[]string{}},
+    }
+    for i=range tests {
+        checkLists(t,"Forward("+strings.Join(tests[i].seeds,",")+")",edgeList(network.Forward(tests[i].seeds,tests[i].depth)),tests[i].edges)
+    }
+}
+func TestBackward(t *testing.T) {
+    var (
+        i int
+        network *Network
+        tests []struct {
+            seeds []string
+            depth float64
+            edges []string
+        }
+    )
+    network=readFixture(t,"network.sif",testSIF,ReadOptions{})
+    tests=[]struct {
+        seeds []string
+        depth float64
+        edges []string
+    }{
+        {[]string{"E"},math.NaN(),[]string{"D E","C E","B D","B C","A B","C A","X A"}},
+        {[]string{"E"},1,[]string{"D E","C E"}},
+        {[]string{"A"},2,[]string{"C A","X A","B C"}},
+        {[]string{"X"},math.NaN(),[]string{}},
+    }
+    for i=range tests {
+        checkLists(t,"Backward("+strings.Join(tests[i].seeds,",")+")",edgeList(network.Backward(tests[i].seeds,tests[i].depth)),tests[i].edges)
+    }
+}
+func TestConnect(t *testing.T) {
+    var (
+        i int
+        err error
+        network,connect *Network
+        tests []struct {
+            sources,targets []string
+            edges []string
+            err error
+        }
+    )
+    network=readFixture(t,"network.sif",testSIF,ReadOptions{})
+    tests=[]struct {
+        sources,targets []string
+        edges []string
+        err error
+    }{
+        {[]string{"A"},[]string{"E"},[]string{"A B","B C","B D","C A","C E","D E"},nil},
+        {[]string{"X"},[]string{"D"},[]string{"X A","A B","B C","B D","C A"},nil},
+        {[]string{"D"},[]string{"C"},[]string{},ErrNoConnecting},
+        {[]string{"F"},[]string{"A"},[]string{},ErrNoForward},
+        {[]string{"A"},[]string{"X"},[]string{},ErrNoBackward},
+    }
+    for i=range tests {
+        connect,err=network.Connect(tests[i].sources,tests[i].targets)
+        if !errors.Is(err,tests[i].err) {
+            t.Errorf("Connect(%v, %v): error %v, want %v",tests[i].sources,tests[i].targets,err,tests[i].err)
+        }
+        checkLists(t,"Connect("+strings.Join(tests[i].sources,",")+", "+strings.Join(tests[i].targets,",")+")",edgeList(connect),tests[i].edges)
+    }
+}
+func TestVia(t *testing.T) {
+    var (
+        i int
+        network *Network
+        tests []struct {
+            waypoints []string
+            all bool
+            edges []string
+        }
+    )
+    network=readFixture(t,"network.sif","S\tactivation\tP\nP\tactivation\tT\nS\tactivation\tQ\nQ\tactivation\tT\nS\tactivation\tR\nR\tactivation\tT\nP\tactivation\tQ\n",ReadOptions{})
+    tests=[]struct {
+        waypoints []string
+        all bool
+        edges []string
+    }{
+        {[]string{"R"},true,[]string{"S R","R T"}},
+        // through every waypoint in the given order
+        {[]string{"P","Q"},true,[]string{"S P","P Q","Q T"}},
+        {[]string{"Q","P"},true,[]string{}},
+        {[]string{"P","R"},true,[]string{}},
+        // through at least one waypoint
+        {[]string{"P","R"},false,[]string{"S P","P T","P Q","Q T","S R","R T"}},
+        {[]string{"Q"},false,[]string{"S Q","S P","P Q","Q T"}},
+    }
+    for i=range tests {
+        checkSets(t,"Via("+strings.Join(tests[i].waypoints,",")+", "+strconv.FormatBool(tests[i].all)+")",edgeList(network.Via([]string{"S"},[]string{"T"},tests[i].waypoints,tests[i].all)),tests[i].edges)
+    }
+}
+func TestWithin(t *testing.T) {
+    var (
+        i int
+        network *Network
+        tests []struct {
+            sources,targets []string
+            maxLength float64
+            edges []string
+        }
+    )
+    network=readFixture(t,"network.sif",testSIF+"A\tactivation\tE\n",ReadOptions{})
+    tests=[]struct {
+        sources,targets []string
+        maxLength float64
+        edges []string
+    }{
+        {[]string{"A"},[]string{"F"},1,[]string{}},
+        {[]string{"A"},[]string{"F"},2,[]string{"A E","E F"}},
+        {[]string{"A"},[]string{"F"},4,[]string{"A B","B C","B D","C E","D E","A E","E F"}},
+        {[]string{"X","B"},[]string{"E"},2,[]string{"B C","B D","C E","D E","X A","A E"}},
+    }
+    for i=range tests {
+        checkSets(t,"Within("+strings.Join(tests[i].sources,",")+", "+strings.Join(tests[i].targets,",")+")",edgeList(network.Within(tests[i].sources,tests[i].targets,tests[i].maxLength)),tests[i].edges)
+    }
+    // the bound is on walks: B -> A only lies on the walk A B A B D
+    network=readFixture(t,"network.sif","A\tactivation\tB\nB\tactivation\tD\nB\tinhibition\tA\n",ReadOptions{})
+    checkSets(t,"Within",edgeList(network.Within([]string{"A"},[]string{"D"},3)),[]string{"A B","B D"})
+    checkSets(t,"Within",edgeList(network.Within([]string{"A"},[]string{"D"},4)),[]string{"A B","B D","B A"})
+}
+func TestStream(t *testing.T) {
+    var (
+        i int
+        err error
+        network,ward *Network
+        tests []struct {
+            seeds []string
+            direction string
+            depth float64
+            edges []string
+            fails bool
+        }
+    )
+    network=readFixture(t,"network.sif",testSIF,ReadOptions{})
+    tests=[]struct {
+        seeds []string
+        direction string
+        depth float64
+        edges []string
+        fails bool
+    }{
+        {[]string{"D"},"up",math.NaN(),[]string{"B D","A B","C A","X A","B C"},false},
+        {[]string{"D"},"up",1,[]string{"B D"},false},
+        {[]string{"D"},"down",math.NaN(),[]string{"D E","E F"},false},
+        {[]string{"D"},"both",math.NaN(),[]string{"B D","A B","C A","X A","B C","D E","E F"},false},
+        {[]string{"D"},"both",1,[]string{"B D","D E"},false},
+        // an edge both upstream and downstream is kept once
+        {[]string{"A"},"both",1,[]string{"C A","X A","A B"},false},
+        {[]string{"D"},"sideways",math.NaN(),[]string{},true},
+    }
+    for i=range tests {
+        ward,err=network.Stream(tests[i].seeds,tests[i].direction,tests[i].depth)
+        if (err!=nil)!=tests[i].fails {
+            t.Errorf("Stream(%v, %s): error %v",tests[i].seeds,tests[i].direction,err)
+        }
+        checkLists(t,"Stream("+strings.Join(tests[i].seeds,",")+", "+tests[i].direction+")",edgeList(ward),tests[i].edges)
+    }
+}
+func TestShortest(t *testing.T) {
+    var (
+        i int
+        network,selfLooped,weighted *Network
+        tests []struct {
+            network *Network
+            sources,targets []string
+            edges []string
+        }
+    )
+    network=readFixture(t,"network.sif",testSIF,ReadOptions{})
+    selfLooped=readFixture(t,"self-looped.sif","A\tactivation\tA\nA\tactivation\tB\n",ReadOptions{})
+    weighted=readFixture(t,"weighted.sif",testWeightedSIF,ReadOptions{Weighted:true})
+    tests=[]struct {
+        network *Network
+        sources,targets []string
+        edges []string
+    }{
+        // both paths of length 3
+        {network,[]string{"A"},[]string{"E"},[]string{"A B","B C","C E","B D","D E"}},
+        {network,[]string{"X"},[]string{"B","F"},[]string{"X A","A B","E F","B C","C E","B D","D E"}},
+        {network,[]string{"F"},[]string{"A"},[]string{}},
+        // a self-loop is the shortest path from a node to itself
+        {selfLooped,[]string{"A"},[]string{"A","B"},[]string{"A A","A B"}},
+        // Dijkstra's algorithm: A -> B -> C -> D weighs 3, A -> D weighs 5
+        {weighted,[]string{"A"},[]string{"D"},[]string{"A B","B C","C D"}},
+        {weighted,[]string{"A"},[]string{"C"},[]string{"A B","B C"}},
+    }
+    for i=range tests {
+        checkSets(t,"Shortest("+strings.Join(tests[i].sources,",")+", "+strings.Join(tests[i].targets,",")+")",edgeList(tests[i].network.Shortest(tests[i].sources,tests[i].targets)),tests[i].edges)
+    }
+}
+func TestBlacklist(t *testing.T) {
+    var (
+        err error
+        network,blacklisted *Network
+    )
+    network=readFixture(t,"network.sif",testSIF,ReadOptions{})
+    blacklisted,err=network.Blacklist([]string{"C","X"})
+    if err!=nil {
+        t.Fatal(err)
+    }
+    checkLists(t,"Blacklist",edgeList(blacklisted),[]string{"A B","B D","D E","E F"})
+    _,err=readFixture(t,"network.sif","A\tactivation\tB\n",ReadOptions{}).Blacklist([]string{"A"})
+    checkError(t,"Blacklist",err,"network empty after blacklisting")
+}
+func TestBlacklistEdges(t *testing.T) {
+    var (
+        i int
+        err error
+        edges [][]string
+        network,blacklisted *Network
+        tests []struct {
+            content string
+            lines []string
+            err string
+        }
+    )
+    network=readFixture(t,"network.sif","A\tactivation\tB\nA\tinhibition\tB\nB\tactivation\tC\tD\nC\tactivation\tD\n",ReadOptions{})
+    tests=[]struct {
+        content string
+        lines []string
+        err string
+    }{
+        // an interaction name, then a whole edge
+        {"A\tinhibition\tB\nB\tC\n",[]string{"A activation B","B activation D","C activation D"},""},
+        // several targets, and removing the last name of an edge
+        {"B\tactivation\tC\tD\nA\tinhibition\tB\nA\tactivation\tB\n",[]string{"C activation D"},""},
+        {"A\tD\n",nil,"A -> D: edge not in network"},
+        {"A\tbinding\tB\n",nil,"A binding B: edge not in network"},
+        {"A\n",nil,"A: expecting source target or source interaction target"},
+        {"",nil,"empty after reading"},
+        {"A\tB\nB\tC\nB\tD\nC\tD\n",nil,"network empty after blacklisting"},
+    }
+    for i=range tests {
+        edges,err=network.ReadEdges(writeFixture(t,"edges.txt",tests[i].content))
+        if err==nil {
+            blacklisted,err=network.BlacklistEdges(edges)
+        }
+        checkError(t,"BlacklistEdges("+strings.ReplaceAll(tests[i].content,"\n","\\n")+")",err,tests[i].err)
+        if err==nil {
+            checkSets(t,"BlacklistEdges("+strings.ReplaceAll(tests[i].content,"\n","\\n")+")",sifLines(blacklisted),tests[i].lines)
+        }
+    }
+}
+func TestNeighbours(t *testing.T) {
+    var (
+        network *Network
+    )
+    network=readFixture(t,"network.sif",testSIF,ReadOptions{})
+    checkLists(t,"Neighbours",network.Neighbours([]string{"A"}),[]string{"B","C","X"})
+    checkLists(t,"Neighbours",network.Neighbours([]string{"A","B"}),[]string{"C","D","X"})
+}
+func TestRootsLeaves(t *testing.T) {
+    var (
+        network *Network
+    )
+    network=readFixture(t,"network.sif",testSIF,ReadOptions{})
+    checkLists(t,"Roots",network.Roots(),[]string{"X"})
+    checkLists(t,"Leaves",network.Leaves(),[]string{"F"})
+}
+// TestExamples checks that the examples give the results shipped with them:
+// out.sif and out-shortest.sif for pathrider connect -s, out.sif for
+// pathrider stream.
+func TestExamples(t *testing.T) {
+    var (
+        err error
+        sources,targets []string
+        network,connect,ward *Network
+        example testExample
+    )
+    for _,example=range testExamples {
+        network,sources,targets=loadExample(t,example)
+        if example.direction=="" {
+            connect,err=network.Connect(sources,targets)
+            if err!=nil {
+                t.Fatal(example.name+": "+err.Error())
+            }
+            checkExample(t,example.name,connect,filepath.Join(example.dir,"out.sif"))
+            checkExample(t,example.name,connect.Shortest(sources,targets),filepath.Join(example.dir,"out-shortest.sif"))
+        } else {
+            ward,err=network.Stream(sources,example.direction,math.NaN())
+            if err!=nil {
+                t.Fatal(example.name+": "+err.Error())
+            }
+            checkExample(t,example.name,ward,filepath.Join(example.dir,"out.sif"))
+        }
+    }
+}
+// TestBaseline checks that Connect, Shortest and Stream give the same edges,
+// in the same order, as the baseline algorithm on the examples.
+func TestBaseline(t *testing.T) {
+    var (
+        err error
+        sources,targets []string
+        network,connect,ward *Network
+        example testExample
+    )
+    if testing.Short() {
+        t.Skip("the baseline algorithm is slow")
+    }
+    for _,example=range testExamples {
+        network,sources,targets=loadExample(t,example)
+        if example.direction=="" {
+            connect,err=network.Connect(sources,targets)
+            if err!=nil {
+                t.Fatal(example.name+": "+err.Error())
+            }
+            checkLists(t,example.name+": Connect",edgeList(connect),joinEdges(baselineConnect(network.Edges(),sources,targets)))
+            checkLists(t,example.name+": Shortest",edgeList(connect.Shortest(sources,targets)),joinEdges(baselineShortest(connect.Edges(),sources,targets)))
+        } else {
+            ward,err=network.Stream(sources,example.direction,math.NaN())
+            if err!=nil {
+                t.Fatal(example.name+": "+err.Error())
+            }
+            checkLists(t,example.name+": Stream",edgeList(ward),joinEdges(baselineStream(network.Edges(),sources,example.direction)))
+        }
+    }
+}
+// The benchmarks run Connect, Shortest (applied to the result of Connect, as
+// pathrider connect -s does) and Stream on the examples, along with the
+// baseline algorithm for comparison (BenchmarkBaseline...).
+func BenchmarkConnect(b *testing.B) {
+    benchmarkExamples(b,false,func(network *Network,sources,targets []string) {network.Connect(sources,targets)})
+}
+func BenchmarkBaselineConnect(b *testing.B) {
+    benchmarkExamples(b,false,func(network *Network,sources,targets []string) {baselineConnect(network.Edges(),sources,targets)})
+}
+func BenchmarkShortest(b *testing.B) {
+    benchmarkExamples(b,false,func(network *Network,sources,targets []string) {
+        var (
+            connect *Network
+        )
+        connect,_=network.Connect(sources,targets)
+        connect.Shortest(sources,targets)
+    })
+}
+func BenchmarkBaselineShortest(b *testing.B) {
+    benchmarkExamples(b,false,func(network *Network,sources,targets []string) {
+        baselineShortest(baselineConnect(network.Edges(),sources,targets),sources,targets)
+    })
+}
+func BenchmarkStream(b *testing.B) {
+    benchmarkExamples(b,true,func(network *Network,seeds,directions []string) {network.Stream(seeds,directions[0],math.NaN())})
+}
+func BenchmarkBaselineStream(b *testing.B) {
+    benchmarkExamples(b,true,func(network *Network,seeds,directions []string) {baselineStream(network.Edges(),seeds,directions[0])})
+}
+// benchmarkExamples runs run on the examples of pathrider connect (with the
+// source and target nodes) or, if stream, of pathrider stream (with the seed
+// nodes and the direction), one sub-benchmark per example.
+func benchmarkExamples(b *testing.B,stream bool,run func(*Network,[]string,[]string)) {
+    var (
+        sources,targets []string
+        network *Network
+        example testExample
+    )
+    for _,example=range testExamples {
+        if (example.direction!="")==stream {
+            network,sources,targets=loadExample(b,example)
+            if stream {
+                targets=[]string{example.direction}
+            }
+            b.Run(example.name,func(b *testing.B) {
+                var (
+                    i int
+                )
+                for i=0;i<b.N;i++ {
+                    run(network,sources,targets)
+                }
+            })
+        }
+    }
+}
+// baselineConnect, baselineShortest and baselineStream are the former
+// list-scanning implementations of Connect, Shortest and Stream, working on
+// source/target pairs, kept as a reference for the tests and the benchmarks.
+func baselineConnect(edges [][]string,sources,targets []string) [][]string {
+    var (
+        forward,backward [][]string
+    )
+    forward=baselineForward(sources,edges)
+    backward=baselineBackward(targets,edges)
+    return baselineIntersect(forward,backward)
+}
+func baselineStream(edges [][]string,seeds []string,direction string) [][]string {
+    var (
+        ward [][]string
+    )
+    if direction=="up" {
+        ward=baselineBackward(seeds,edges)
+    } else {
+        ward=baselineForward(seeds,edges)
+    }
+    return ward
+}
+func baselineShortest(edges [][]string,sources,targets []string) [][]string {
+    var (
+        source,target string
+        edge,selfLooped []string
+        noSelfLoop,layers,shortest,allShortest [][]string
+        nodeSucc,nodePred map[string][]string
+        edgeSucc,edgePred map[string]map[string][][]string
+    )
+    for _,edge=range edges {
+        if edge[0]==edge[1] {
+            selfLooped=append(selfLooped,edge[0])
+        } else {
+            noSelfLoop=append(noSelfLoop,copyList(edge))
+        }
+    }
+    nodeSucc,edgeSucc=baselineAdjacency(noSelfLoop,0)
+    for _,source=range sources {
+        layers=baselineLayers(source,nodeSucc,edgeSucc)
+        nodePred,edgePred=baselineAdjacency(layers,1)
+        for _,target=range targets {
+            if (source==target) && isInList(selfLooped,source) {
+                shortest=[][]string{{source,target}}
+            } else {
+                shortest=baselineShortestPaths(source,target,nodePred,edgePred)
+            }
+            for _,edge=range shortest {
+                if !baselineIsInList2(allShortest,edge) {
+                    allShortest=append(allShortest,copyList(edge))
+                }
+            }
+        }
+    }
+    return allShortest
+}
+// baselineAdjacency returns the successors (from 0) or the predecessors (from
+// 1) of the nodes and of the edges.
+func baselineAdjacency(edges [][]string,from int) (map[string][]string,map[string]map[string][][]string) {
+    var (
+        node,node2,node3 string
+        edge []string
+        nodeAdj map[string][]string
+        edgeAdj map[string]map[string][][]string
+    )
+    nodeAdj=make(map[string][]string)
+    edgeAdj=make(map[string]map[string][][]string)
+    for _,edge=range edges {
+        for _,node=range edge {
+            nodeAdj[node]=[]string{}
+        }
+        edgeAdj[edge[0]]=make(map[string][][]string)
+    }
+    for _,edge=range edges {
+        nodeAdj[edge[from]]=append(nodeAdj[edge[from]],edge[1-from])
+        edgeAdj[edge[0]][edge[1]]=[][]string{}
+    }
+    for node=range nodeAdj {
+        for _,node2=range nodeAdj[node] {
+            for _,node3=range nodeAdj[node2] {
+                if from==0 {
+                    edgeAdj[node][node2]=append(edgeAdj[node][node2],[]string{node2,node3})
+                } else {
+                    edgeAdj[node2][node]=append(edgeAdj[node2][node],[]string{node3,node2})
+                }
+            }
+        }
+    }
+    return nodeAdj,edgeAdj
+}
+func baselineForward(seeds []string,edges [][]string) [][]string {
+    var (
+        seed,nsucc string
+        edge,esucc []string
+        forward,newCheck,toCheck [][]string
+        nodeSucc map[string][]string
+        edgeSucc map[string]map[string][][]string
+    )
+    nodeSucc,edgeSucc=baselineAdjacency(edges,0)
+    for _,seed=range seeds {
+        for _,nsucc=range nodeSucc[seed] {
+            forward=append(forward,[]string{seed,nsucc})
+            newCheck=append(newCheck,[]string{seed,nsucc})
+        }
+    }
+    for len(newCheck)!=0 {
+        toCheck=newCheck
+        newCheck=[][]string{}
+        for _,edge=range toCheck {
+            for _,esucc=range edgeSucc[edge[0]][edge[1]] {
+                if !baselineIsInList2(forward,esucc) {
+                    forward=append(forward,copyList(esucc))
+                    newCheck=append(newCheck,copyList(esucc))
+                }
+            }
+        }
+    }
+    return forward
+}
+func baselineBackward(seeds []string,edges [][]string) [][]string {
+    var (
+        seed,npred string
+        edge,epred []string
+        backward,newCheck,toCheck [][]string
+        nodePred map[string][]string
+        edgePred map[string]map[string][][]string
+    )
+    nodePred,edgePred=baselineAdjacency(edges,1)
+    for _,seed=range seeds {
+        for _,npred=range nodePred[seed] {
+            backward=append(backward,[]string{npred,seed})
+            newCheck=append(newCheck,[]string{npred,seed})
+        }
+    }
+    for len(newCheck)!=0 {
+        toCheck=newCheck
+        newCheck=[][]string{}
+        for _,edge=range toCheck {
+            for _,epred=range edgePred[edge[0]][edge[1]] {
+                if !baselineIsInList2(backward,epred) {
+                    backward=append(backward,copyList(epred))
+                    newCheck=append(newCheck,copyList(epred))
+                }
+            }
+        }
+    }
+    return backward
+}
+func baselineIntersect(edges1,edges2 [][]string) [][]string {
+    var (
+        edge []string
+        intersect [][]string
+    )
+    for _,edge=range edges1 {
+        if baselineIsInList2(edges2,edge) {
+            intersect=append(intersect,copyList(edge))
+        }
+    }
+    return intersect
+}
+func baselineLayers(seed string,nodeSucc map[string][]string,edgeSucc map[string]map[string][][]string) [][]string {
+    var (
+        nsucc string
+        edge,esucc,visited []string
+        layer,newLayer,edges [][]string
+    )
+    for _,nsucc=range nodeSucc[seed] {
+        newLayer=append(newLayer,[]string{seed,nsucc})
+        edges=append(edges,[]string{seed,nsucc})
+    }
+    for len(newLayer)!=0 {
+        for _,edge=range newLayer {
+            visited=append(visited,edge[1])
+        }
+        layer=newLayer
+        newLayer=[][]string{}
+        for _,edge=range layer {
+            for _,esucc=range edgeSucc[edge[0]][edge[1]] {
+                if !baselineIsInList2(edges,esucc) && !isInList(visited,esucc[1]) {
+                    newLayer=append(newLayer,copyList(esucc))
+                    edges=append(edges,copyList(esucc))
+                }
+            }
+        }
+    }
+    return edges
+}
+func baselineShortestPaths(source,target string,nodePred map[string][]string,edgePred map[string]map[string][][]string) [][]string {
+    var (
+        found bool
+        npred string
+        edge,epred []string
+        newCheck,toCheck,shortest [][]string
+    )
+    for _,npred=range nodePred[target] {
+        shortest=append(shortest,[]string{npred,target})
+        newCheck=append(newCheck,[]string{npred,target})
+    }
+    for (len(newCheck)!=0) && !found {
+        for _,edge=range newCheck {
+            if edge[0]==source {
+                found=true
+                break
+            }
+        }
+        if !found {
+            toCheck=newCheck
+            newCheck=[][]string{}
+            for _,edge=range toCheck {
+                for _,epred=range edgePred[edge[0]][edge[1]] {
+                    if !baselineIsInList2(shortest,epred) {
+                        shortest=append(shortest,copyList(epred))
+                        newCheck=append(newCheck,copyList(epred))
+                    }
+                }
+            }
+        }
+    }
+    if !found {
+        shortest=[][]string{}
+    }
+    return shortest
+}
+func baselineIsInList2(list2 [][]string,thatList []string) bool {
+    var (
+        found bool
+        list []string
+    )
+    for _,list=range list2 {
+        if (list[0]==thatList[0]) && (list[1]==thatList[1]) {
+            found=true
+            break
+        }
+    }
+    return found
+}
+// loadExample reads the network and the node files of an example.
+func loadExample(t testing.TB,example testExample) (*Network,[]string,[]string) {
+    var (
+        err error
+        sources,targets []string
+        network *Network
+    )
+    network,err=Read(filepath.Join(example.dir,example.networkFile),ReadOptions{})
+    if err==nil {
+        sources,err=network.ReadNodes(filepath.Join(example.dir,example.sourceFile))
+    }
+    if (err==nil) && (example.targetFile!="") {
+        targets,err=network.ReadNodes(filepath.Join(example.dir,example.targetFile))
+    }
+    if err!=nil {
+        t.Fatal(example.name+": "+err.Error())
+    }
+    return network,sources,targets
+}
+// checkExample checks that result is written as in outFile.
+func checkExample(t *testing.T,name string,result *Network,outFile string) {
+    var (
+        err error
+        want []byte
+    )
+    want,err=os.ReadFile(outFile)
+    if err!=nil {
+        t.Fatal(err)
+    }
+    checkFile(t,name,result.Write,"out.sif",string(want))
+}
+// writeFixture writes content to a file of a temporary directory and returns
+// its path.
+func writeFixture(t testing.TB,name,content string) string {
+    var (
+        err error
+        file string
+    )
+    file=filepath.Join(t.TempDir(),name)
+    err=os.WriteFile(file,[]byte(content),0644)
+    if err!=nil {
+        t.Fatal(err)
+    }
+    return file
+}
+// readFixture reads a network from content.
+func readFixture(t testing.TB,name,content string,options ReadOptions) *Network {
+    var (
+        err error
+        network *Network
+    )
+    network,err=Read(writeFixture(t,name,content),options)
+    if err!=nil {
+        t.Fatal(name+": "+err.Error())
+    }
+    return network
+}
+// edgeList returns the edges of the network as "source target", in order.
+func edgeList(network *Network) []string {
+    return joinEdges(network.Edges())
+}
+func joinEdges(edges [][]string) []string {
+    var (
+        edge,list []string
+    )
+    list=[]string{}
+    for _,edge=range edges {
+        list=append(list,strings.Join(edge," "))
+    }
+    return list
+}
+// sifLines returns the interactions of the network as "source interaction
+// target".
+func sifLines(network *Network) []string {
+    var (
+        i,edge int
+        name,line string
+        lines []string
+    )
+    lines=[]string{}
+    for edge=range network.edges {
+        for i,name=range network.edgeNames[edge] {
+            line=network.nodes[network.edges[edge][0]]+" "+name+" "+network.nodes[network.edges[edge][1]]
+            if network.weighted {
+                line+=" "+formatWeight(network.weights[edge][i])
+            }
+            lines=append(lines,line)
+        }
+    }
+    return lines
+}
+// pathList returns the paths as their nodes separated by spaces.
+func pathList(paths []Path) []string {
+    var (
+        path Path
+        list []string
+    )
+    list=[]string{}
+    for _,path=range paths {
+        list=append(list,strings.Join(path.Nodes," "))
+    }
+    return list
+}
+func checkLists(t *testing.T,name string,got,want []string) {
+    var (
+        i int
+    )
+    t.Helper()
+    if len(got)!=len(want) {
+        t.Errorf("%s: got %q, want %q",name,got,want)
+    } else {
+        for i=range got {
+            if got[i]!=want[i] {
+                t.Errorf("%s: got %q, want %q",name,got,want)
+                break
+            }
+        }
+    }
+}
+// checkSets is like checkLists but ignores the order.
+func checkSets(t *testing.T,name string,got,want []string) {
+    var (
+        element string
+    )
+    t.Helper()
+    if len(got)!=len(want) {
+        t.Errorf("%s: got %q, want %q",name,got,want)
+    } else {
+        for _,element=range want {
+            if !isInList(got,element) {
+                t.Errorf("%s: got %q, want %q",name,got,want)
+                break
+            }
+        }
+    }
+}
+// checkError checks that err contains want, or is nil if want is empty.
+func checkError(t *testing.T,name string,err error,want string) {
+    t.Helper()
+    if (want=="") && (err!=nil) {
+        t.Errorf("%s: unexpected error: %v",name,err)
+    } else if (want!="") && ((err==nil) || !strings.Contains(err.Error(),want)) {
+        t.Errorf("%s: error %v, want %q",name,err,want)
+    }
+}
+// checkFile checks that write writes want to a file of the given name.
+func checkFile(t *testing.T,name string,write func(string) error,fileName,want string) {
+    var (
+        err error
+        file string
+        got []byte
+    )
+    t.Helper()
+    file=filepath.Join(t.TempDir(),fileName)
+    err=write(file)
+    if err==nil {
+        got,err=os.ReadFile(file)
+    }
+    if err!=nil {
+        t.Errorf("%s: %v",name,err)
+    } else if string(got)!=want {
+        t.Errorf("%s: got\n%s\nwant\n%s",name,got,want)
+    }
+}
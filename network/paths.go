@@ -0,0 +1,242 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "math"
+    "strconv"
+    "strings"
+)
+// Path is a path of a network: it goes from Nodes[i] to Nodes[i+1] through
+// the interactions Names[i], weighing Weights[i] if the network is weighted
+// (Weights is nil otherwise).
+type Path struct {
+    Nodes []string
+    Names [][]string
+    Weights [][]float64
+}
+// Length returns the number of edges of the path.
+func (path Path) Length() int {
+    return len(path.Names)
+}
+// Paths enumerates the simple paths going from each source node to each
+// target node, by increasing length, up to maxLength edges and up to maxCount
+// paths per source/target pair (NaN for no limit). When a source node is also
+// a target node, the paths going from it to itself are its simple cycles.
+func (network *Network) Paths(sources,targets []string,maxLength,maxCount float64) []Path {
+    var (
+        source,target int
+        edgePath []int
+        edgePaths [][]int
+        paths []Path
+    )
+    for _,source=range network.nodeList(sources) {
+        for _,target=range network.nodeList(targets) {
+            edgePaths=append(edgePaths,network.simplePaths(source,target,maxLength,maxCount)...)
+        }
+    }
+    for _,edgePath=range edgePaths {
+        paths=append(paths,network.toPath(edgePath))
+    }
+    return paths
+}
+// WritePaths writes paths to a TSV file with the columns source, target,
+// length, nodes and interactions, plus weights if the paths are weighted.
+// Nodes and the interactions of successive edges are separated by ";", and the
+// interaction names of a same edge by "|", the weights following the layout of
+// the interactions.
+func WritePaths(pathFile string,paths []Path) error {
+    var (
+        weighted bool
+        names,steps,line []string
+        lines [][]string
+        path Path
+    )
+    for _,path=range paths {
+        weighted=weighted || (path.Weights!=nil)
+    }
+    lines=append(lines,[]string{"source","target","length","nodes","interactions"})
+    if weighted {
+        lines[0]=append(lines[0],"weights")
+    }
+    for _,path=range paths {
+        steps=[]string{}
+        for _,names=range path.Names {
+            steps=append(steps,strings.Join(names,"|"))
+        }
+        line=[]string{
+            path.Nodes[0],
+            path.Nodes[len(path.Nodes)-1],
+            strconv.Itoa(path.Length()),
+            strings.Join(path.Nodes,";"),
+            strings.Join(steps,";"),
+        }
+        if weighted {
+            line=append(line,joinWeights(path))
+        }
+        lines=append(lines,line)
+    }
+    return writeTable(pathFile,lines)
+}
+// joinWeights lays out the weights of a path as its interactions are laid out
+// in WritePaths.
+func joinWeights(path Path) string {
+    var (
+        weight float64
+        weightSteps,edgeWeights []string
+        weights []float64
+    )
+    for _,weights=range path.Weights {
+        edgeWeights=[]string{}
+        for _,weight=range weights {
+            edgeWeights=append(edgeWeights,formatWeight(weight))
+        }
+        weightSteps=append(weightSteps,strings.Join(edgeWeights,"|"))
+    }
+    return strings.Join(weightSteps,";")
+}
+// simplePaths returns the simple paths going from source to target, as edge
+// IDs, by increasing length, up to maxLength edges and up to maxCount paths
+// (NaN for no limit).
+func (network *Network) simplePaths(source,target int,maxLength,maxCount float64) [][]int {
+    var (
+        length float64
+        finder *pathFinder
+    )
+    finder=network.newPathFinder(source,target,maxCount)
+    length=1
+    for (length<=float64(len(network.nodes))) && !(length>maxLength) && finder.extendable && !finder.isFull() {
+        finder.extendable=false
+        finder.search(source,int(length))
+        length+=1
+    }
+    return finder.paths
+}
+// pathFinder enumerates by depth-first search the simple paths of a given
+// length going from a source node to a target node, pruning with the distances
+// to the target node.
+type pathFinder struct {
+    network *Network
+    target int
+    maxCount float64
+    extendable bool
+    distances []float64
+    visited []bool
+    current []int
+    paths [][]int
+}
+func (network *Network) newPathFinder(source,target int,maxCount float64) *pathFinder {
+    var (
+        finder *pathFinder
+    )
+    finder=&pathFinder{
+        network:network,
+        target:target,
+        maxCount:maxCount,
+        extendable:true,
+        distances:network.distancesTo([]int{target}),
+        visited:make([]bool,len(network.nodes)),
+    }
+    finder.visited[source]=true
+    return finder
+}
+func (finder *pathFinder) isFull() bool {
+    return float64(len(finder.paths))>=finder.maxCount
+}
+// search extends the current path from node until it reaches the target node
+// with exactly length edges. It records whether some partial path was too
+// short to reach the target node within length edges, since otherwise longer
+// searches would find nothing.
+func (finder *pathFinder) search(node,length int) {
+    var (
+        edge,next int
+    )
+    for _,edge=range finder.network.succ[node] {
+        if finder.isFull() {
+            break
+        }
+        next=finder.network.edges[edge][1]
+        if next==finder.target {
+            if len(finder.current)+1==length {
+                finder.paths=append(finder.paths,append(copyInts(finder.current),edge))
+            }
+        } else if !finder.visited[next] && !math.IsInf(finder.distances[next],1) {
+            if float64(len(finder.current)+1)+finder.distances[next]<=float64(length) {
+                finder.visited[next]=true
+                finder.current=append(finder.current,edge)
+                finder.search(next,length)
+                finder.current=finder.current[:len(finder.current)-1]
+                finder.visited[next]=false
+            } else {
+                finder.extendable=true
+            }
+        }
+    }
+}
+// distancesTo returns the length of the shortest paths from each node to the
+// nearest target node (+Inf if it does not reach any).
+func (network *Network) distancesTo(targets []int) []float64 {
+    return network.distances(targets,false)
+}
+// distancesFrom returns the length of the shortest paths from the nearest seed
+// node to each node (+Inf if no seed node reaches it).
+func (network *Network) distancesFrom(seeds []int) []float64 {
+    return network.distances(seeds,true)
+}
+// distances performs a breadth-first search from the seed nodes, downstream if
+// forward and upstream otherwise, the seed nodes being at distance 0.
+func (network *Network) distances(seeds []int,forward bool) []float64 {
+    var (
+        node,edge,next,seed int
+        distances []float64
+        edges,toCheck,newCheck []int
+    )
+    distances=make([]float64,len(network.nodes))
+    for node=range distances {
+        distances[node]=math.Inf(1)
+    }
+    for _,seed=range seeds {
+        if math.IsInf(distances[seed],1) {
+            distances[seed]=0
+            newCheck=append(newCheck,seed)
+        }
+    }
+    for len(newCheck)!=0 {
+        toCheck=newCheck
+        newCheck=[]int{}
+        for _,node=range toCheck {
+            if forward {
+                edges=network.succ[node]
+            } else {
+                edges=network.pred[node]
+            }
+            for _,edge=range edges {
+                if forward {
+                    next=network.edges[edge][1]
+                } else {
+                    next=network.edges[edge][0]
+                }
+                if math.IsInf(distances[next],1) {
+                    distances[next]=distances[node]+1
+                    newCheck=append(newCheck,next)
+                }
+            }
+        }
+    }
+    return distances
+}
+func (network *Network) toPath(edgePath []int) Path {
+    var (
+        edge int
+        path Path
+    )
+    path.Nodes=[]string{network.nodes[network.edges[edgePath[0]][0]]}
+    for _,edge=range edgePath {
+        path.Nodes=append(path.Nodes,network.nodes[network.edges[edge][1]])
+        path.Names=append(path.Names,copyList(network.edgeNames[edge]))
+        if network.weighted {
+            path.Weights=append(path.Weights,append([]float64{},network.weights[edge]...))
+        }
+    }
+    return path
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "math"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+func TestPaths(t *testing.T) {
+    var (
+        i int
+        network *Network
+        tests []struct {
+            sources,targets []string
+            maxLength,maxCount float64
+            paths []string
+        }
+    )
+    network=readFixture(t,"network.sif",testSIF,ReadOptions{})
+    tests=[]struct {
+        sources,targets []string
+        maxLength,maxCount float64
+        paths []string
+    }{
+        {[]string{"A"},[]string{"F"},math.NaN(),math.NaN(),[]string{"A B C E F","A B D E F"}},
+        {[]string{"A"},[]string{"F"},3,math.NaN(),[]string{}},
+        {[]string{"A"},[]string{"F"},math.NaN(),1,[]string{"A B C E F"}},
+        // by increasing length
+        {[]string{"A"},[]string{"E"},math.NaN(),math.NaN(),[]string{"A B C E","A B D E"}},
+        {[]string{"B"},[]string{"E"},math.NaN(),math.NaN(),[]string{"B C E","B D E"}},
+        {[]string{"B"},[]string{"E","A"},math.NaN(),math.NaN(),[]string{"B C E","B D E","B C A"}},
+        // the paths from a node to itself are its simple cycles
+        {[]string{"A"},[]string{"A"},math.NaN(),math.NaN(),[]string{"A B C A"}},
+        {[]string{"F"},[]string{"A"},math.NaN(),math.NaN(),[]string{}},
+    }
+    for i=range tests {
+        checkLists(t,"Paths("+strings.Join(tests[i].sources,",")+", "+strings.Join(tests[i].targets,",")+")",pathList(network.Paths(tests[i].sources,tests[i].targets,tests[i].maxLength,tests[i].maxCount)),tests[i].paths)
+    }
+}
+func TestWritePaths(t *testing.T) {
+    var (
+        network *Network
+    )
+    network=readFixture(t,"network.sif","A\tactivation\tB\nA\tbinding\tB\nB\tinhibition\tC\nA\tactivation\tC\n",ReadOptions{})
+    checkFile(t,"WritePaths",func(file string) error {return WritePaths(file,network.Paths([]string{"A"},[]string{"C"},math.NaN(),math.NaN()))},"paths.tsv",
+        "source\ttarget\tlength\tnodes\tinteractions\n"+
+        "A\tC\t1\tA;C\tactivation\n"+
+        "A\tC\t2\tA;B;C\tactivation|binding;inhibition\n",
+    )
+    checkFile(t,"WritePaths",func(file string) error {return WritePaths(file,readFixture(t,"weighted.sif",testWeightedSIF,ReadOptions{Weighted:true}).Paths([]string{"A"},[]string{"D"},2,math.NaN()))},"paths.tsv",
+        "source\ttarget\tlength\tnodes\tinteractions\tweights\n"+
+        "A\tD\t1\tA;D\tinhibition\t5\n"+
+        "A\tD\t2\tA;C;D\tactivation;activation\t3;1\n",
+    )
+    if WritePaths(filepath.Join(t.TempDir(),"paths.tsv"),nil)==nil {
+        t.Errorf("WritePaths: no error without paths")
+    }
+}
@@ -0,0 +1,170 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "encoding/json"
+    "errors"
+    "math"
+    "strconv"
+)
+// Reachability tells how a source node reaches a target node: the length of
+// the shortest paths (+Inf if unreachable), their number and the number of
+// intermediate nodes lying on them.
+type Reachability struct {
+    Source,Target string
+    Reachable bool
+    Length float64
+    Paths float64
+    Intermediates int
+}
+// Reachability computes the reachability of each target node from each source
+// node, from the same breadth-first search (or Dijkstra's algorithm if the
+// network is weighted) as Shortest. Every source/target pair is reported, the
+// source or target nodes which are not in the network (e.g. lacking connecting
+// paths in the result of Connect) reaching and being reached by no node.
+func (network *Network) Reachability(sources,targets []string) []Reachability {
+    var (
+        inSource,inTarget bool
+        source,target,edge,node int
+        sourceName,targetName string
+        shortest []int
+        lengths,counts []float64
+        layerPred [][]int
+        inPaths []bool
+        reach Reachability
+        reaches []Reachability
+    )
+    for _,sourceName=range sources {
+        source,inSource=network.nodeIDs[sourceName]
+        if inSource {
+            if network.weighted {
+                layerPred=network.getWeightedLayers(source)
+            } else {
+                layerPred=network.getLayers(source)
+            }
+            lengths=make([]float64,len(network.nodes))
+            counts=make([]float64,len(network.nodes))
+            for node=range counts {
+                counts[node]=math.NaN()
+            }
+            counts[source]=1
+        }
+        for _,targetName=range targets {
+            target,inTarget=network.nodeIDs[targetName]
+            reach=Reachability{Source:sourceName,Target:targetName,Length:math.Inf(1)}
+            if inSource && inTarget && (source==target) && network.isSelfLooped(source) {
+                reach.Reachable,reach.Length,reach.Paths=true,network.pathLength([]int{network.edgeIDs[[2]int{source,target}]}),1
+            } else if inSource && inTarget && (len(layerPred[target])!=0) {
+                reach.Reachable=true
+                if source==target {
+                    for _,edge=range layerPred[target] {
+                        reach.Paths+=network.countPaths(network.edges[edge][0],layerPred,lengths,counts)
+                    }
+                    edge=layerPred[target][0]
+                    reach.Length=lengths[network.edges[edge][0]]+network.pathLength([]int{edge})
+                } else {
+                    reach.Paths=network.countPaths(target,layerPred,lengths,counts)
+                    reach.Length=lengths[target]
+                }
+                if network.weighted {
+                    shortest=network.weightedShortestPaths(source,target,layerPred)
+                } else {
+                    shortest=network.shortestPaths(source,target,layerPred)
+                }
+                inPaths=make([]bool,len(network.nodes))
+                for _,edge=range shortest {
+                    for _,node=range network.edges[edge] {
+                        if (node!=source) && (node!=target) && !inPaths[node] {
+                            inPaths[node]=true
+                            reach.Intermediates+=1
+                        }
+                    }
+                }
+            }
+            reaches=append(reaches,reach)
+        }
+    }
+    return reaches
+}
+// countPaths returns the number of shortest paths going from the seed of
+// layerPred to node, memoized in counts (NaN if not yet computed, the seed
+// counting 1), and records their length in lengths.
+func (network *Network) countPaths(node int,layerPred [][]int,lengths,counts []float64) float64 {
+    var (
+        edge int
+        count float64
+    )
+    if math.IsNaN(counts[node]) {
+        count=0
+        for _,edge=range layerPred[node] {
+            count+=network.countPaths(network.edges[edge][0],layerPred,lengths,counts)
+        }
+        edge=layerPred[node][0]
+        lengths[node]=lengths[network.edges[edge][0]]+network.pathLength([]int{edge})
+        counts[node]=count
+    }
+    return counts[node]
+}
+// WriteReachability writes reachabilities to a TSV file with the columns
+// source, target, reachable (yes or no), length (NA if unreachable), paths
+// and intermediates.
+func WriteReachability(tableFile string,reaches []Reachability) error {
+    var (
+        reachable,length string
+        lines [][]string
+        reach Reachability
+    )
+    lines=append(lines,[]string{"source","target","reachable","length","paths","intermediates"})
+    for _,reach=range reaches {
+        if reach.Reachable {
+            reachable,length="yes",formatWeight(reach.Length)
+        } else {
+            reachable,length="no","NA"
+        }
+        lines=append(lines,[]string{
+            reach.Source,
+            reach.Target,
+            reachable,
+            length,
+            strconv.FormatFloat(reach.Paths,'f',-1,64),
+            strconv.Itoa(reach.Intermediates),
+        })
+    }
+    return writeTable(tableFile,lines)
+}
+// WriteReachabilityJSON writes reachabilities to a JSON file as an array of
+// objects with the keys source, target, reachable, length (null if
+// unreachable), paths and intermediates.
+func WriteReachabilityJSON(jsonFile string,reaches []Reachability) error {
+    type object struct {
+        Source string `json:"source"`
+        Target string `json:"target"`
+        Reachable bool `json:"reachable"`
+        Length *float64 `json:"length"`
+        Paths float64 `json:"paths"`
+        Intermediates int `json:"intermediates"`
+    }
+    var (
+        err error
+        data []byte
+        objects []object
+        reach Reachability
+    )
+    for _,reach=range reaches {
+        objects=append(objects,object{Source:reach.Source,Target:reach.Target,Reachable:reach.Reachable,Paths:reach.Paths,Intermediates:reach.Intermediates})
+        if reach.Reachable {
+            objects[len(objects)-1].Length=new(float64)
+            *objects[len(objects)-1].Length=reach.Length
+        }
+    }
+    if len(objects)==0 {
+        err=errors.New("empty before writing")
+    } else {
+        data,err=json.MarshalIndent(objects,"","    ")
+        if err==nil {
+            err=writeFile(jsonFile,append(data,'\n'))
+        }
+    }
+    return err
+}
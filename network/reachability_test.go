@@ -0,0 +1,88 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "math"
+    "path/filepath"
+    "testing"
+)
+func TestReachability(t *testing.T) {
+    var (
+        i int
+        reaches []Reachability
+        network,selfLooped,weighted *Network
+        tests []struct {
+            network *Network
+            source,target string
+            reach Reachability
+        }
+    )
+    network=readFixture(t,"network.sif",testSIF,ReadOptions{})
+    selfLooped=readFixture(t,"self-looped.sif","A\tactivation\tA\nA\tactivation\tB\n",ReadOptions{})
+    weighted=readFixture(t,"weighted.sif",testWeightedSIF,ReadOptions{Weighted:true})
+    tests=[]struct {
+        network *Network
+        source,target string
+        reach Reachability
+    }{
+        // through B, C and E or B, D and E
+        {network,"A","F",Reachability{Source:"A",Target:"F",Reachable:true,Length:4,Paths:2,Intermediates:4}},
+        {network,"X","F",Reachability{Source:"X",Target:"F",Reachable:true,Length:5,Paths:2,Intermediates:5}},
+        // a node reaches itself through its shortest cycles
+        {network,"A","A",Reachability{Source:"A",Target:"A",Reachable:true,Length:3,Paths:1,Intermediates:2}},
+        {network,"F","A",Reachability{Source:"F",Target:"A",Reachable:false,Length:math.Inf(1)}},
+        {selfLooped,"A","A",Reachability{Source:"A",Target:"A",Reachable:true,Length:1,Paths:1}},
+        {weighted,"A","D",Reachability{Source:"A",Target:"D",Reachable:true,Length:3,Paths:1,Intermediates:2}},
+    }
+    for i=range tests {
+        reaches=tests[i].network.Reachability([]string{tests[i].source},[]string{tests[i].target})
+        if (len(reaches)!=1) || (reaches[0]!=tests[i].reach) {
+            t.Errorf("Reachability(%s, %s): got %+v, want %+v",tests[i].source,tests[i].target,reaches,tests[i].reach)
+        }
+    }
+    // every pair is reported, the nodes not in the network reaching nothing
+    reaches=network.Reachability([]string{"A","X"},[]string{"E","F","Z"})
+    if len(reaches)!=6 {
+        t.Errorf("Reachability: got %d reachabilities, want one per source/target pair",len(reaches))
+    } else if reaches[2]!=(Reachability{Source:"A",Target:"Z",Reachable:false,Length:math.Inf(1)}) {
+        t.Errorf("Reachability(A, Z): got %+v",reaches[2])
+    }
+}
+func TestWriteReachability(t *testing.T) {
+    var (
+        reaches []Reachability
+    )
+    reaches=readFixture(t,"network.sif",testSIF,ReadOptions{}).Reachability([]string{"A","F"},[]string{"F"})
+    checkFile(t,"WriteReachability",func(file string) error {return WriteReachability(file,reaches)},"reachability.tsv",
+        "source\ttarget\treachable\tlength\tpaths\tintermediates\n"+
+        "A\tF\tyes\t4\t2\t4\n"+
+        "F\tF\tno\tNA\t0\t0\n",
+    )
+    checkFile(t,"WriteReachabilityJSON",func(file string) error {return WriteReachabilityJSON(file,reaches)},"reachability.json",
+        "[\n"+
+        "    {\n"+
+        "        \"source\": \"A\",\n"+
+        "        \"target\": \"F\",\n"+
+        "        \"reachable\": true,\n"+
+        "        \"length\": 4,\n"+
+        "        \"paths\": 2,\n"+
+        "        \"intermediates\": 4\n"+
+        "    },\n"+
+        "    {\n"+
+        "        \"source\": \"F\",\n"+
+        "        \"target\": \"F\",\n"+
+        "        \"reachable\": false,\n"+
+        "        \"length\": null,\n"+
+        "        \"paths\": 0,\n"+
+        "        \"intermediates\": 0\n"+
+        "    }\n"+
+        "]\n",
+    )
+    if WriteReachability(filepath.Join(t.TempDir(),"reachability.tsv"),nil)==nil {
+        t.Errorf("WriteReachability: no error without reachabilities")
+    }
+    if WriteReachabilityJSON(filepath.Join(t.TempDir(),"reachability.json"),nil)==nil {
+        t.Errorf("WriteReachabilityJSON: no error without reachabilities")
+    }
+}
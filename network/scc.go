@@ -0,0 +1,174 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "sort"
+    "strconv"
+)
+// Components returns the strongly connected components of the network with
+// Tarjan's algorithm, in topological order of the condensation (a component
+// comes before the components it reaches). The nodes of a component are in
+// order of appearance in the network.
+func (network *Network) Components() [][]string {
+    var (
+        node,i int
+        component []int
+        names []string
+        components [][]string
+        finder *componentFinder
+    )
+    finder=&componentFinder{
+        network:network,
+        index:make([]int,len(network.nodes)),
+        lowLink:make([]int,len(network.nodes)),
+        onStack:make([]bool,len(network.nodes)),
+    }
+    for node=range network.nodes {
+        finder.index[node]=-1
+    }
+    for node=range network.nodes {
+        if finder.index[node]==-1 {
+            finder.connect(node)
+        }
+    }
+    for i=len(finder.components)-1;i>=0;i-- {
+        component=finder.components[i]
+        sort.Ints(component)
+        names=[]string{}
+        for _,node=range component {
+            names=append(names,network.nodes[node])
+        }
+        components=append(components,names)
+    }
+    return components
+}
+// Condense returns the condensation of the network, namely the acyclic network
+// whose nodes are the components returned by Components and whose edges are
+// the interactions linking the nodes of two different components. A component
+// is named after its node if it is acyclic (a single node without self-loop),
+// and scc1, scc2, ... otherwise, suffixed with -2, -3, ... when a node of the
+// network already has this name. The condensation is unweighted.
+func (network *Network) Condense(components [][]string) *Network {
+    var (
+        i,edge int
+        name string
+        names []string
+        componentOf []int
+        condensed *Network
+    )
+    condensed=New()
+    names=network.componentNames(components)
+    componentOf=network.componentIndex(components)
+    for i=range components {
+        condensed.addNode(names[i])
+    }
+    for edge=range network.edges {
+        if componentOf[network.edges[edge][0]]!=componentOf[network.edges[edge][1]] {
+            for _,name=range network.edgeNames[edge] {
+                condensed.addEdge(names[componentOf[network.edges[edge][0]]],name,names[componentOf[network.edges[edge][1]]],1)
+            }
+        }
+    }
+    return condensed
+}
+// WriteComponents writes a TSV file with the columns node, component and size,
+// giving for each node of the network its component, named as in Condense,
+// and the number of nodes of this component.
+func (network *Network) WriteComponents(tableFile string,components [][]string) error {
+    var (
+        i int
+        node string
+        names []string
+        values map[string][]string
+    )
+    values=make(map[string][]string)
+    names=network.componentNames(components)
+    for i=range components {
+        for _,node=range components[i] {
+            values[node]=[]string{names[i],strconv.Itoa(len(components[i]))}
+        }
+    }
+    return network.WriteNodeTable(tableFile,[]string{"component","size"},values)
+}
+// componentFinder holds the state of Tarjan's algorithm. The components are
+// found in reverse topological order of the condensation.
+type componentFinder struct {
+    network *Network
+    count int
+    index,lowLink,stack []int
+    onStack []bool
+    components [][]int
+}
+func (finder *componentFinder) connect(node int) {
+    var (
+        edge,next,member int
+        component []int
+    )
+    finder.index[node]=finder.count
+    finder.lowLink[node]=finder.count
+    finder.count+=1
+    finder.stack=append(finder.stack,node)
+    finder.onStack[node]=true
+    for _,edge=range finder.network.succ[node] {
+        next=finder.network.edges[edge][1]
+        if finder.index[next]==-1 {
+            finder.connect(next)
+            if finder.lowLink[next]<finder.lowLink[node] {
+                finder.lowLink[node]=finder.lowLink[next]
+            }
+        } else if finder.onStack[next] && (finder.index[next]<finder.lowLink[node]) {
+            finder.lowLink[node]=finder.index[next]
+        }
+    }
+    if finder.lowLink[node]==finder.index[node] {
+        member=-1
+        for member!=node {
+            member=finder.stack[len(finder.stack)-1]
+            finder.stack=finder.stack[:len(finder.stack)-1]
+            finder.onStack[member]=false
+            component=append(component,member)
+        }
+        finder.components=append(finder.components,component)
+    }
+}
+func (network *Network) componentIndex(components [][]string) []int {
+    var (
+        i int
+        node string
+        componentOf []int
+    )
+    componentOf=make([]int,len(network.nodes))
+    for i=range components {
+        for _,node=range components[i] {
+            componentOf[network.nodeIDs[node]]=i
+        }
+    }
+    return componentOf
+}
+func (network *Network) componentNames(components [][]string) []string {
+    var (
+        i,cyclic,suffix int
+        found bool
+        names []string
+    )
+    names=make([]string,len(components))
+    for i=range components {
+        if (len(components[i])==1) && !network.isSelfLooped(network.nodeIDs[components[i][0]]) {
+            names[i]=components[i][0]
+        } else {
+            cyclic+=1
+            names[i]="scc"+strconv.Itoa(cyclic)
+            _,found=network.nodeIDs[names[i]]
+            suffix=1
+            for found {
+                suffix+=1
+                _,found=network.nodeIDs["scc"+strconv.Itoa(cyclic)+"-"+strconv.Itoa(suffix)]
+            }
+            if suffix>1 {
+                names[i]="scc"+strconv.Itoa(cyclic)+"-"+strconv.Itoa(suffix)
+            }
+        }
+    }
+    return names
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "testing"
+)
+func TestComponents(t *testing.T) {
+    var (
+        i,j int
+        components [][]string
+        tests []struct {
+            content string
+            components [][]string
+        }
+    )
+    tests=[]struct {
+        content string
+        components [][]string
+    }{
+        // in topological order of the condensation
+        {testSIF,[][]string{{"X"},{"A","B","C"},{"D"},{"E"},{"F"}}},
+        {testCycleSIF,[][]string{{"E"},{"A","B","C","D"}}},
+        {"A\tactivation\tB\nB\tactivation\tC\n",[][]string{{"A"},{"B"},{"C"}}},
+    }
+    for i=range tests {
+        components=readFixture(t,"network.sif",tests[i].content,ReadOptions{}).Components()
+        if len(components)!=len(tests[i].components) {
+            t.Errorf("Components: got %v, want %v",components,tests[i].components)
+        } else {
+            for j=range components {
+                checkLists(t,"Components",components[j],tests[i].components[j])
+            }
+        }
+    }
+}
+func TestCondense(t *testing.T) {
+    var (
+        i int
+        network,condensed *Network
+        tests []struct {
+            content string
+            nodes,lines []string
+        }
+    )
+    tests=[]struct {
+        content string
+        nodes,lines []string
+    }{
+        {testSIF,[]string{"X","scc1","D","E","F"},[]string{"X activation scc1","scc1 activation D","scc1 activation E","D activation E","E activation F"}},
+        // a self-looped node is a cyclic component, C and D coming first
+        {"A\tactivation\tA\nA\tinhibition\tB\nC\tactivation\tB\nC\tactivation\tD\nD\tactivation\tC\n",[]string{"scc1","scc2","B"},[]string{"scc2 inhibition B","scc1 activation B"}},
+        // the name of a cyclic component must not be the one of a node
+        {"scc1\tactivation\tA\nscc1-2\tactivation\tA\nA\tactivation\tB\nB\tactivation\tA\n",[]string{"scc1-2","scc1","scc1-3"},[]string{"scc1 activation scc1-3","scc1-2 activation scc1-3"}},
+    }
+    for i=range tests {
+        network=readFixture(t,"network.sif",tests[i].content,ReadOptions{})
+        condensed=network.Condense(network.Components())
+        checkLists(t,"Condense",condensed.Nodes(),tests[i].nodes)
+        checkSets(t,"Condense",sifLines(condensed),tests[i].lines)
+    }
+}
+func TestWriteComponents(t *testing.T) {
+    var (
+        network *Network
+    )
+    network=readFixture(t,"network.sif",testSIF,ReadOptions{})
+    checkFile(t,"WriteComponents",func(file string) error {return network.WriteComponents(file,network.Components())},"components.tsv",
+        "node\tcomponent\tsize\n"+
+        "A\tscc1\t3\n"+
+        "B\tscc1\t3\n"+
+        "C\tscc1\t3\n"+
+        "D\tD\t1\n"+
+        "E\tE\t1\n"+
+        "F\tF\t1\n"+
+        "X\tX\t1\n",
+    )
+}
@@ -0,0 +1,164 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "encoding/csv"
+    "errors"
+    "io"
+    "os"
+    "strconv"
+)
+// readSIF reads the lines of a SIF file into the network. Besides the usual
+// "source interaction target" lines, it accepts the lines listing several
+// targets ("source interaction target1 target2 ...") and the lines made of a
+// single isolated node. With options.Weighted, the last column of the lines
+// listing targets is their weight.
+func (network *Network) readSIF(file io.Reader,options ReadOptions) error {
+    var (
+        err error
+        lineNum int
+        weight float64
+        target string
+        line []string
+        reader *csv.Reader
+    )
+    reader=csv.NewReader(file)
+    reader.Comma='\t'
+    if options.SkipComments {
+        reader.Comment='#'
+    } else {
+        reader.Comment=0
+    }
+    reader.FieldsPerRecord=-1
+    reader.LazyQuotes=false
+    reader.TrimLeadingSpace=true
+    reader.ReuseRecord=true
+    for err==nil {
+        line,err=reader.Read()
+        if err==nil {
+            lineNum,_=reader.FieldPos(0)
+            if isInList(line,"") {
+                err=errors.New("line "+strconv.Itoa(lineNum)+": empty field")
+            } else if len(line)==1 {
+                network.addNode(line[0])
+            } else if len(line)==2 {
+                err=errors.New("line "+strconv.Itoa(lineNum)+": missing target, expecting: source interaction target")
+            } else if !options.Weighted {
+                for _,target=range line[2:] {
+                    network.AddEdge(line[0],line[1],target)
+                }
+            } else if len(line)==3 {
+                err=errors.New("line "+strconv.Itoa(lineNum)+": missing weight, expecting: source interaction target weight")
+            } else {
+                weight,err=parseWeight(line[len(line)-1])
+                if err!=nil {
+                    err=errors.New("line "+strconv.Itoa(lineNum)+": "+err.Error())
+                } else {
+                    for _,target=range line[2:len(line)-1] {
+                        network.AddWeightedEdge(line[0],line[1],target,weight)
+                    }
+                }
+            }
+        }
+    }
+    if err==io.EOF {
+        err=nil
+    }
+    return err
+}
+// Write writes the network to a SIF file, one line per interaction name. If
+// the network is weighted, the weights are written in a fourth column.
+func (network *Network) Write(networkFile string) error {
+    var (
+        err error
+        i,edge int
+        name string
+        line []string
+        lines [][]string
+        file *os.File
+        writer *csv.Writer
+    )
+    for edge=range network.edges {
+        for i,name=range network.edgeNames[edge] {
+            line=[]string{network.nodes[network.edges[edge][0]],name,network.nodes[network.edges[edge][1]]}
+            if network.weighted {
+                line=append(line,formatWeight(network.weights[edge][i]))
+            }
+            lines=append(lines,line)
+        }
+    }
+    if len(lines)==0 {
+        err=errors.New("empty before writing")
+    } else {
+        file,err=createFile(networkFile)
+        defer closeFile(file)
+        if err==nil {
+            writer=csv.NewWriter(file)
+            writer.Comma='\t'
+            writer.UseCRLF=false
+            err=writer.WriteAll(lines)
+        }
+    }
+    return err
+}
+// WriteEdgeTable writes a TSV file with the columns source, interaction,
+// target and the given attribute columns, one line per interaction name as in
+// SIF files. The attribute values of the edge source -> target are
+// values[source][target], empty if missing.
+func (network *Network) WriteEdgeTable(tableFile string,columns []string,values map[string]map[string][]string) error {
+    var (
+        edge int
+        name,source,target string
+        row []string
+        lines [][]string
+    )
+    lines=append(lines,append([]string{"source","interaction","target"},columns...))
+    for edge=range network.edges {
+        source,target=network.nodes[network.edges[edge][0]],network.nodes[network.edges[edge][1]]
+        for _,name=range network.edgeNames[edge] {
+            row=make([]string,len(columns))
+            copy(row,values[source][target])
+            lines=append(lines,append([]string{source,name,target},row...))
+        }
+    }
+    return writeTable(tableFile,lines)
+}
+// WriteNodeTable writes a TSV file with the columns node and the given
+// attribute columns, one line per node. The attribute values of node are
+// values[node], empty if missing.
+func (network *Network) WriteNodeTable(tableFile string,columns []string,values map[string][]string) error {
+    var (
+        node string
+        row []string
+        lines [][]string
+    )
+    lines=append(lines,append([]string{"node"},columns...))
+    for _,node=range network.nodes {
+        row=make([]string,len(columns))
+        copy(row,values[node])
+        lines=append(lines,append([]string{node},row...))
+    }
+    return writeTable(tableFile,lines)
+}
+// writeTable writes lines to a TSV file, the first line being the header.
+func writeTable(tableFile string,lines [][]string) error {
+    var (
+        err error
+        file *os.File
+        writer *csv.Writer
+    )
+    if len(lines)<2 {
+        err=errors.New("empty before writing")
+    } else {
+        file,err=createFile(tableFile)
+        defer closeFile(file)
+        if err==nil {
+            writer=csv.NewWriter(file)
+            writer.Comma='\t'
+            writer.UseCRLF=false
+            err=writer.WriteAll(lines)
+        }
+    }
+    return err
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "path/filepath"
+    "strings"
+    "testing"
+)
+func TestReadSIF(t *testing.T) {
+    var (
+        i int
+        err error
+        network *Network
+        tests []struct {
+            content string
+            options ReadOptions
+            nodes,lines []string
+            err string
+        }
+    )
+    tests=[]struct {
+        content string
+        options ReadOptions
+        nodes,lines []string
+        err string
+    }{
+        {"A\tactivation\tB\nA\tinhibition\tB\nB\tactivation\tC\n",ReadOptions{},[]string{"A","B","C"},[]string{"A activation B","A inhibition B","B activation C"},""},
+        // duplicates are removed
+        {"A\tactivation\tB\nA\tactivation\tB\n",ReadOptions{},[]string{"A","B"},[]string{"A activation B"},""},
+        // several targets and an isolated node
+        {"A\tactivation\tB\tC\nD\n",ReadOptions{},[]string{"A","B","C","D"},[]string{"A activation B","A activation C"},""},
+        {"# comment\nA\tactivation\tB\n",ReadOptions{SkipComments:true},[]string{"A","B"},[]string{"A activation B"},""},
+        // without SkipComments, "# comment" is an isolated node
+        {"# comment\nA\tactivation\tB\n",ReadOptions{},[]string{"# comment","A","B"},[]string{"A activation B"},""},
+        {"A\tactivation\n",ReadOptions{},nil,nil,"line 1: missing target"},
+        {"A\tactivation\tB\nB\tactivation\tC\t\n",ReadOptions{},nil,nil,"line 2: empty field"},
+        {"A\n",ReadOptions{},nil,nil,"empty after reading"},
+        // the last column is the weight of the targets
+        {"A\tactivation\tB\tC\t0.5\nC\tinhibition\tA\t2\n",ReadOptions{Weighted:true},[]string{"A","B","C"},[]string{"A activation B 0.5","A activation C 0.5","C inhibition A 2"},""},
+        {"A\tactivation\tB\n",ReadOptions{Weighted:true},nil,nil,"line 1: missing weight"},
+        {"A\tactivation\tB\t-1\n",ReadOptions{Weighted:true},nil,nil,"-1: weight must be a positive number"},
+        {"A\tactivation\tB\theavy\n",ReadOptions{Weighted:true},nil,nil,"heavy: weight must be a positive number"},
+    }
+    for i=range tests {
+        network,err=Read(writeFixture(t,"network.sif",tests[i].content),tests[i].options)
+        checkError(t,"Read("+strings.ReplaceAll(tests[i].content,"\n","\\n")+")",err,tests[i].err)
+        if err==nil {
+            checkLists(t,"Read",network.Nodes(),tests[i].nodes)
+            checkLists(t,"Read",sifLines(network),tests[i].lines)
+        }
+    }
+}
+func TestWrite(t *testing.T) {
+    var (
+        i int
+        tests []struct {
+            content string
+            options ReadOptions
+        }
+    )
+    tests=[]struct {
+        content string
+        options ReadOptions
+    }{
+        {testSIF,ReadOptions{}},
+        {testWeightedSIF,ReadOptions{Weighted:true}},
+        {"A\tactivation\tB\nA\tinhibition\tB\n",ReadOptions{}},
+    }
+    for i=range tests {
+        checkFile(t,"Write",readFixture(t,"network.sif",tests[i].content,tests[i].options).Write,"out.sif",tests[i].content)
+    }
+    if New().Write(filepath.Join(t.TempDir(),"out.sif"))==nil {
+        t.Errorf("Write: no error for an empty network")
+    }
+}
+func TestWriteNodeTable(t *testing.T) {
+    var (
+        network *Network
+    )
+    network=readFixture(t,"network.sif","A\tactivation\tB\nB\tinhibition\tC\n",ReadOptions{})
+    checkFile(t,"WriteNodeTable",func(file string) error {return network.WriteNodeTable(file,[]string{"seeds","count"},map[string][]string{"A":{"A","1"},"C":{"A,B","2"}})},"nodes.tsv",
+        "node\tseeds\tcount\n"+
+        "A\tA\t1\n"+
+        "B\t\t\n"+
+        "C\tA,B\t2\n",
+    )
+}
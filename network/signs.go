@@ -0,0 +1,237 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "encoding/csv"
+    "errors"
+    "os"
+    "strings"
+)
+// SignMap maps interaction names to signs: 1 for activations, -1 for
+// inhibitions and 0 for unsigned interactions. Interaction names which are not
+// in the map are signed from their keywords: activation and expression are
+// positive, inhibition and repression are negative, the others are unsigned.
+type SignMap map[string]int
+// ErrTooManyPaths is returned by SignedConnect when a source/target pair has
+// too many simple paths to enumerate.
+var ErrTooManyPaths=errors.New("too many connecting paths to sign")
+// ReadSignMap reads a sign map from a TSV file with 2 columns: the interaction
+// name and its sign ("+", "-" or "0").
+func ReadSignMap(signFile string) (SignMap,error) {
+    var (
+        err error
+        line []string
+        lines [][]string
+        signMap SignMap
+        file *os.File
+        reader *csv.Reader
+    )
+    signMap=make(SignMap)
+    file,err=openFile(signFile)
+    defer closeFile(file)
+    if err==nil {
+        reader=csv.NewReader(file)
+        reader.Comma='\t'
+        reader.Comment='#'
+        reader.FieldsPerRecord=2
+        reader.LazyQuotes=false
+        reader.TrimLeadingSpace=true
+        reader.ReuseRecord=true
+        lines,err=reader.ReadAll()
+        if err==nil {
+            for _,line=range lines {
+                if line[1]=="+" {
+                    signMap[line[0]]=1
+                } else if line[1]=="-" {
+                    signMap[line[0]]=-1
+                } else if line[1]=="0" {
+                    signMap[line[0]]=0
+                } else {
+                    err=errors.New(line[0]+": "+line[1]+": unknown sign, expecting one of: +, -, 0")
+                    break
+                }
+            }
+            if (err==nil) && (len(signMap)==0) {
+                err=errors.New("empty after reading")
+            }
+        }
+    }
+    return signMap,err
+}
+// Sign returns the sign of an interaction name. Names joining several
+// interactions with commas (e.g. "activation_PPrel,phosphorylation_PPrel")
+// get the sign of their signed parts, or 0 if these disagree.
+func (signMap SignMap) Sign(name string) int {
+    var (
+        positive,negative bool
+        sign int
+    )
+    positive,negative=signMap.signs([]string{name})
+    if positive && !negative {
+        sign=1
+    } else if negative && !positive {
+        sign=-1
+    } else {
+        sign=0
+    }
+    return sign
+}
+// signs tells whether an edge having the given interaction names can be
+// positive or negative.
+func (signMap SignMap) signs(names []string) (bool,bool) {
+    var (
+        found,positive,negative bool
+        sign int
+        name,part string
+    )
+    for _,name=range names {
+        sign,found=signMap[name]
+        if found {
+            positive=positive || (sign==1)
+            negative=negative || (sign==-1)
+        } else {
+            for _,part=range strings.Split(name,",") {
+                sign,found=signMap[part]
+                if !found {
+                    sign=keywordSign(part)
+                }
+                positive=positive || (sign==1)
+                negative=negative || (sign==-1)
+            }
+        }
+    }
+    return positive,negative
+}
+// PathSign returns the overall sign of a path, namely the product of the signs
+// of its edges: 1 (positive) or -1 (negative), or 0 if some edge is unsigned or
+// both positive and negative.
+func (signMap SignMap) PathSign(path Path) int {
+    var (
+        positive,negative bool
+        sign int
+        names []string
+    )
+    sign=1
+    for _,names=range path.Names {
+        positive,negative=signMap.signs(names)
+        if positive==negative {
+            sign=0
+            break
+        } else if negative {
+            sign=-sign
+        }
+    }
+    return sign
+}
+func keywordSign(name string) int {
+    var (
+        sign int
+    )
+    name=strings.ToLower(name)
+    if strings.Contains(name,"activation") || strings.Contains(name,"expression") {
+        sign=1
+    } else if strings.Contains(name,"inhibition") || strings.Contains(name,"repression") {
+        sign=-1
+    } else {
+        sign=0
+    }
+    return sign
+}
+// SignedConnect returns the simple paths connecting the source nodes to the
+// target nodes whose overall sign, as given by PathSign, is sign (1 or -1), so
+// that the paths going through unsigned edges or through edges being both
+// positive and negative are never kept. The paths must also go through the
+// waypoints as in Via (no constraint without waypoints) and have at most
+// maxLength edges (NaN for no limit), so that these constraints hold for each
+// kept path. Since the simple paths are enumerated one by one, it returns
+// ErrTooManyPaths if a source/target pair has more than maxCount of them (NaN
+// for no limit).
+func (network *Network) SignedConnect(sources,targets,waypoints []string,all bool,signMap SignMap,sign int,maxLength,maxCount float64) (*Network,error) {
+    var (
+        err error
+        source,target,edge int
+        edgePath,edges,stops []int
+        edgePaths [][]int
+        inEdges []bool
+        edgeSigns []int
+    )
+    edgeSigns=make([]int,len(network.edges))
+    for edge=range network.edges {
+        edgeSigns[edge]=signMap.PathSign(Path{Names:[][]string{network.edgeNames[edge]}})
+    }
+    stops=network.nodeList(waypoints)
+    inEdges=make([]bool,len(network.edges))
+    for _,source=range network.nodeList(sources) {
+        for _,target=range network.nodeList(targets) {
+            edgePaths=network.simplePaths(source,target,maxLength,maxCount+1)
+            if float64(len(edgePaths))>maxCount {
+                err=ErrTooManyPaths
+                break
+            }
+            for _,edgePath=range edgePaths {
+                if (edgePathSign(edgePath,edgeSigns)==sign) && network.visits(edgePath,stops,all) {
+                    for _,edge=range edgePath {
+                        inEdges[edge]=true
+                    }
+                }
+            }
+        }
+        if err!=nil {
+            break
+        }
+    }
+    if err==nil {
+        for edge=range network.edges {
+            if inEdges[edge] {
+                edges=append(edges,edge)
+            }
+        }
+    }
+    return network.sub(edges),err
+}
+// edgePathSign returns the overall sign of a path given as edge IDs, namely the
+// product of the signs of its edges.
+func edgePathSign(edgePath,edgeSigns []int) int {
+    var (
+        edge,sign int
+    )
+    sign=1
+    for _,edge=range edgePath {
+        sign*=edgeSigns[edge]
+    }
+    return sign
+}
+// visits tells whether a path given as edge IDs goes through at least one of
+// the waypoints, or through all of them in order if all, which is the case
+// without waypoints.
+func (network *Network) visits(edgePath,waypoints []int,all bool) bool {
+    var (
+        visited bool
+        edge,node,next int
+        nodes []int
+    )
+    nodes=[]int{network.edges[edgePath[0]][0]}
+    for _,edge=range edgePath {
+        nodes=append(nodes,network.edges[edge][1])
+    }
+    if len(waypoints)==0 {
+        visited=true
+    } else if all {
+        next=0
+        for _,node=range nodes {
+            if (next<len(waypoints)) && (node==waypoints[next]) {
+                next+=1
+            }
+        }
+        visited=next==len(waypoints)
+    } else {
+        for _,node=range nodes {
+            if isInInts(waypoints,node) {
+                visited=true
+                break
+            }
+        }
+    }
+    return visited
+}
@@ -0,0 +1,187 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "math"
+    "strconv"
+    "testing"
+)
+// testSignedSIF connects A to D through negative paths (A B C D, A C D), an
+// unsigned path (A E D, binding being unsigned by default) and a path through
+// an edge being both positive and negative (A H D).
+const testSignedSIF="A\tactivation\tB\n"+
+    "B\tinhibition\tC\n"+
+    "A\tinhibition\tC\n"+
+    "C\tactivation\tD\n"+
+    "A\tactivation\tE\n"+
+    "E\tbinding\tD\n"+
+    "A\tactivation\tH\n"+
+    "A\tinhibition\tH\n"+
+    "H\texpression\tD\n"
+func TestReadSignMap(t *testing.T) {
+    var (
+        i int
+        err error
+        name string
+        signMap SignMap
+        tests []struct {
+            content string
+            signMap SignMap
+            err string
+        }
+    )
+    tests=[]struct {
+        content string
+        signMap SignMap
+        err string
+    }{
+        {"# name\tsign\nbinding\t+\nPPrel\t-\nactivation\t0\n",SignMap{"binding":1,"PPrel":-1,"activation":0},""},
+        {"binding\t?\n",nil,"binding: ?: unknown sign"},
+        {"binding\n",nil,"wrong number of fields"},
+        {"# nothing\n",nil,"empty after reading"},
+    }
+    for i=range tests {
+        signMap,err=ReadSignMap(writeFixture(t,"signs.tsv",tests[i].content))
+        checkError(t,"ReadSignMap",err,tests[i].err)
+        if (err==nil) && (len(signMap)!=len(tests[i].signMap)) {
+            t.Errorf("ReadSignMap: got %v, want %v",signMap,tests[i].signMap)
+        } else if err==nil {
+            for name=range tests[i].signMap {
+                if signMap[name]!=tests[i].signMap[name] {
+                    t.Errorf("ReadSignMap: got %v, want %v",signMap,tests[i].signMap)
+                    break
+                }
+            }
+        }
+    }
+}
+func TestSign(t *testing.T) {
+    var (
+        i int
+        signMap SignMap
+        tests []struct {
+            name string
+            sign int
+        }
+    )
+    signMap=SignMap{"binding":-1,"activation_PPrel":0}
+    tests=[]struct {
+        name string
+        sign int
+    }{
+        {"activation",1},
+        {"Expression",1},
+        {"inhibition",-1},
+        {"repression",-1},
+        {"unknown",0},
+        // the map overrides the keywords
+        {"binding",-1},
+        {"activation_PPrel",0},
+        {"activation_PPrel,phosphorylation_PPrel",0},
+        {"activation_GErel,expression_GErel",1},
+        {"activation_PPrel,inhibition_PPrel",-1},
+        {"activation_GErel,inhibition_PPrel",0},
+    }
+    for i=range tests {
+        if signMap.Sign(tests[i].name)!=tests[i].sign {
+            t.Errorf("Sign(%q): got %v, want %v",tests[i].name,signMap.Sign(tests[i].name),tests[i].sign)
+        }
+    }
+}
+func TestPathSign(t *testing.T) {
+    var (
+        i int
+        signMap SignMap
+        tests []struct {
+            names [][]string
+            sign int
+        }
+    )
+    signMap=SignMap{"binding":-1}
+    tests=[]struct {
+        names [][]string
+        sign int
+    }{
+        {[][]string{{"activation"},{"expression"}},1},
+        {[][]string{{"activation"},{"inhibition"}},-1},
+        {[][]string{{"inhibition"},{"repression"}},1},
+        {[][]string{{"activation"},{"binding"}},-1},
+        // an unsigned edge, then an edge both positive and negative
+        {[][]string{{"activation"},{"unknown"}},0},
+        {[][]string{{"activation","inhibition"},{"activation"}},0},
+    }
+    for i=range tests {
+        if signMap.PathSign(Path{Names:tests[i].names})!=tests[i].sign {
+            t.Errorf("PathSign(%v): got %v, want %v",tests[i].names,signMap.PathSign(Path{Names:tests[i].names}),tests[i].sign)
+        }
+    }
+}
+func TestSignedConnect(t *testing.T) {
+    var (
+        i int
+        err error
+        network,signed *Network
+        tests []struct {
+            signMap SignMap
+            sign int
+            waypoints []string
+            all bool
+            maxLength float64
+            edges []string
+        }
+    )
+    network=readFixture(t,"network.sif",testSignedSIF,ReadOptions{})
+    tests=[]struct {
+        signMap SignMap
+        sign int
+        waypoints []string
+        all bool
+        maxLength float64
+        edges []string
+    }{
+        // A E D is unsigned and A H D goes through an edge being both
+        // positive and negative
+        {SignMap{},1,nil,false,math.NaN(),[]string{}},
+        {SignMap{},-1,nil,false,math.NaN(),[]string{"A B","B C","A C","C D"}},
+        {SignMap{"binding":-1},1,nil,false,math.NaN(),[]string{}},
+        {SignMap{"binding":-1},-1,nil,false,math.NaN(),[]string{"A B","B C","A C","C D","A E","E D"}},
+        // the constraints of Within and Via hold for each kept path
+        {SignMap{},-1,nil,false,2,[]string{"A C","C D"}},
+        {SignMap{"binding":-1},-1,[]string{"B","E"},false,math.NaN(),[]string{"A B","B C","C D","A E","E D"}},
+        {SignMap{},-1,[]string{"B","C"},true,math.NaN(),[]string{"A B","B C","C D"}},
+        {SignMap{},-1,[]string{"C","B"},true,math.NaN(),[]string{}},
+    }
+    for i=range tests {
+        signed,err=network.SignedConnect([]string{"A"},[]string{"D"},tests[i].waypoints,tests[i].all,tests[i].signMap,tests[i].sign,tests[i].maxLength,math.NaN())
+        checkError(t,"SignedConnect",err,"")
+        checkSets(t,"SignedConnect("+strconv.Itoa(tests[i].sign)+")",edgeList(signed),tests[i].edges)
+    }
+    signed,_=network.SignedConnect([]string{"D"},[]string{"A"},nil,false,SignMap{},1,math.NaN(),math.NaN())
+    checkSets(t,"SignedConnect",edgeList(signed),[]string{})
+    // the walk A B C A B C around the negative feedback loop is not a path
+    network=readFixture(t,"network.sif","A\tactivation\tB\nB\tactivation\tC\nC\tinhibition\tA\n",ReadOptions{})
+    signed,_=network.SignedConnect([]string{"A"},[]string{"C"},nil,false,SignMap{},-1,math.NaN(),math.NaN())
+    checkSets(t,"SignedConnect",edgeList(signed),[]string{})
+    signed,_=network.SignedConnect([]string{"A"},[]string{"C"},nil,false,SignMap{},1,math.NaN(),math.NaN())
+    checkSets(t,"SignedConnect",edgeList(signed),[]string{"A B","B C"})
+    _,err=readFixture(t,"network.sif",testSignedSIF,ReadOptions{}).SignedConnect([]string{"A"},[]string{"D"},nil,false,SignMap{},-1,math.NaN(),3)
+    if err!=ErrTooManyPaths {
+        t.Errorf("SignedConnect: got error %v, want %v",err,ErrTooManyPaths)
+    }
+}
+func TestWriteEdgeTable(t *testing.T) {
+    var (
+        network *Network
+    )
+    network=readFixture(t,"network.sif","A\tactivation\tB\nA\tbinding\tB\nB\tinhibition\tC\n",ReadOptions{})
+    checkFile(t,"WriteEdgeTable",func(file string) error {return network.WriteEdgeTable(file,[]string{"sign","note"},map[string]map[string][]string{"A":{"B":{"+","x"}},"B":{"C":{"-"}}})},"edges.tsv",
+        "source\tinteraction\ttarget\tsign\tnote\n"+
+        "A\tactivation\tB\t+\tx\n"+
+        "A\tbinding\tB\t+\tx\n"+
+        "B\tinhibition\tC\t-\t\n",
+    )
+    if !network.HasEdge("A","B") || network.HasEdge("B","A") || network.HasEdge("A","Z") {
+        t.Errorf("HasEdge: wrong answers for A -> B, B -> A and A -> Z")
+    }
+}
@@ -0,0 +1,254 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "encoding/json"
+    "math"
+    "sort"
+    "strconv"
+    "strings"
+)
+// Stats summarises a network. Edges are source/target pairs and interactions
+// are the interaction names of the edges. Names lists the interaction names by
+// decreasing frequency, NameCounts giving their number of edges. InDegrees[d]
+// (resp. OutDegrees[d]) is the number of nodes having d predecessors (resp.
+// successors). Components are the weakly connected components of the network
+// and Diameter is the length, in number of edges, of the longest shortest path
+// (-1 if not computed).
+type Stats struct {
+    Nodes,Edges,Interactions,SelfLoops,Duplicates int
+    Names []string
+    NameCounts []int
+    InDegrees,OutDegrees []int
+    Sources,Sinks []string
+    Components,LargestComponent,Diameter int
+}
+// Duplicates returns the number of interactions ignored while building the
+// network because they were already in it (e.g. duplicated lines of a SIF
+// file).
+func (network *Network) Duplicates() int {
+    return network.duplicates
+}
+// Stats computes the statistics of the network. The diameter is computed only
+// if diameter is true, since it requires a breadth-first search from each
+// node, namely O(nodes*(nodes+edges)) time.
+func (network *Network) Stats(diameter bool) Stats {
+    var (
+        node,edge,size int
+        name string
+        distance float64
+        counts map[string]int
+        stats Stats
+    )
+    stats=Stats{
+        Nodes:len(network.nodes),
+        Edges:len(network.edges),
+        Duplicates:network.duplicates,
+        InDegrees:[]int{},
+        OutDegrees:[]int{},
+        Sources:network.Roots(),
+        Sinks:network.Leaves(),
+        Diameter:-1,
+    }
+    if diameter {
+        stats.Diameter=0
+    }
+    counts=make(map[string]int)
+    for edge=range network.edges {
+        if network.isSelfLoop(edge) {
+            stats.SelfLoops+=1
+        }
+        for _,name=range network.edgeNames[edge] {
+            stats.Interactions+=1
+            if counts[name]==0 {
+                stats.Names=append(stats.Names,name)
+            }
+            counts[name]+=1
+        }
+    }
+    sort.SliceStable(stats.Names,func(i,j int) bool {
+        return counts[stats.Names[i]]>counts[stats.Names[j]]
+    })
+    for _,name=range stats.Names {
+        stats.NameCounts=append(stats.NameCounts,counts[name])
+    }
+    for node=range network.nodes {
+        for len(stats.InDegrees)<=len(network.pred[node]) {
+            stats.InDegrees=append(stats.InDegrees,0)
+        }
+        stats.InDegrees[len(network.pred[node])]+=1
+        for len(stats.OutDegrees)<=len(network.succ[node]) {
+            stats.OutDegrees=append(stats.OutDegrees,0)
+        }
+        stats.OutDegrees[len(network.succ[node])]+=1
+        if diameter {
+            for _,distance=range network.distancesFrom([]int{node}) {
+                if !math.IsInf(distance,1) && (int(distance)>stats.Diameter) {
+                    stats.Diameter=int(distance)
+                }
+            }
+        }
+    }
+    for _,size=range network.weakComponents() {
+        stats.Components+=1
+        if size>stats.LargestComponent {
+            stats.LargestComponent=size
+        }
+    }
+    return stats
+}
+// WriteStats writes stats to a text file, one statistic per line ("name:
+// value", the diameter only if computed) followed by the interaction names,
+// the degree distributions, the sources and the sinks, one item per indented
+// line.
+func WriteStats(statsFile string,stats Stats) error {
+    var (
+        i int
+        name string
+        lines []string
+    )
+    lines=[]string{
+        "nodes: "+strconv.Itoa(stats.Nodes),
+        "edges: "+strconv.Itoa(stats.Edges),
+        "interactions: "+strconv.Itoa(stats.Interactions),
+        "self-loops: "+strconv.Itoa(stats.SelfLoops),
+        "duplicates: "+strconv.Itoa(stats.Duplicates),
+        "sources: "+strconv.Itoa(len(stats.Sources)),
+        "sinks: "+strconv.Itoa(len(stats.Sinks)),
+        "components: "+strconv.Itoa(stats.Components),
+        "largest component: "+strconv.Itoa(stats.LargestComponent),
+    }
+    if stats.Diameter!=-1 {
+        lines=append(lines,"diameter: "+strconv.Itoa(stats.Diameter))
+    }
+    lines=append(lines,"","interaction names:")
+    for i,name=range stats.Names {
+        lines=append(lines,"    "+name+": "+strconv.Itoa(stats.NameCounts[i]))
+    }
+    lines=append(lines,"","in-degrees:")
+    for i=range stats.InDegrees {
+        if stats.InDegrees[i]!=0 {
+            lines=append(lines,"    "+strconv.Itoa(i)+": "+strconv.Itoa(stats.InDegrees[i]))
+        }
+    }
+    lines=append(lines,"","out-degrees:")
+    for i=range stats.OutDegrees {
+        if stats.OutDegrees[i]!=0 {
+            lines=append(lines,"    "+strconv.Itoa(i)+": "+strconv.Itoa(stats.OutDegrees[i]))
+        }
+    }
+    lines=append(lines,"","sources:")
+    for _,name=range stats.Sources {
+        lines=append(lines,"    "+name)
+    }
+    lines=append(lines,"","sinks:")
+    for _,name=range stats.Sinks {
+        lines=append(lines,"    "+name)
+    }
+    return writeFile(statsFile,[]byte(strings.Join(lines,"\n")+"\n"))
+}
+// WriteStatsJSON writes stats to a JSON file as an object with the keys of
+// WriteStats, the interaction names and the degree distributions being arrays
+// of objects.
+func WriteStatsJSON(jsonFile string,stats Stats) error {
+    type nameCount struct {
+        Name string `json:"name"`
+        Count int `json:"count"`
+    }
+    type degreeCount struct {
+        Degree int `json:"degree"`
+        Nodes int `json:"nodes"`
+    }
+    type object struct {
+        Nodes int `json:"nodes"`
+        Edges int `json:"edges"`
+        Interactions int `json:"interactions"`
+        SelfLoops int `json:"self_loops"`
+        Duplicates int `json:"duplicates"`
+        Components int `json:"components"`
+        LargestComponent int `json:"largest_component"`
+        Diameter *int `json:"diameter,omitempty"`
+        Names []nameCount `json:"interaction_names"`
+        InDegrees []degreeCount `json:"in_degrees"`
+        OutDegrees []degreeCount `json:"out_degrees"`
+        Sources []string `json:"sources"`
+        Sinks []string `json:"sinks"`
+    }
+    var (
+        err error
+        i int
+        name string
+        data []byte
+        obj object
+    )
+    obj=object{
+        Nodes:stats.Nodes,
+        Edges:stats.Edges,
+        Interactions:stats.Interactions,
+        SelfLoops:stats.SelfLoops,
+        Duplicates:stats.Duplicates,
+        Components:stats.Components,
+        LargestComponent:stats.LargestComponent,
+        Names:[]nameCount{},
+        InDegrees:[]degreeCount{},
+        OutDegrees:[]degreeCount{},
+        Sources:append([]string{},stats.Sources...),
+        Sinks:append([]string{},stats.Sinks...),
+    }
+    if stats.Diameter!=-1 {
+        obj.Diameter=&stats.Diameter
+    }
+    for i,name=range stats.Names {
+        obj.Names=append(obj.Names,nameCount{Name:name,Count:stats.NameCounts[i]})
+    }
+    for i=range stats.InDegrees {
+        if stats.InDegrees[i]!=0 {
+            obj.InDegrees=append(obj.InDegrees,degreeCount{Degree:i,Nodes:stats.InDegrees[i]})
+        }
+    }
+    for i=range stats.OutDegrees {
+        if stats.OutDegrees[i]!=0 {
+            obj.OutDegrees=append(obj.OutDegrees,degreeCount{Degree:i,Nodes:stats.OutDegrees[i]})
+        }
+    }
+    data,err=json.MarshalIndent(obj,"","    ")
+    if err==nil {
+        err=writeFile(jsonFile,append(data,'\n'))
+    }
+    return err
+}
+// weakComponents returns the sizes of the weakly connected components of the
+// network, namely its connected components when ignoring edge directions.
+func (network *Network) weakComponents() []int {
+    var (
+        node,start,edge,next,size int
+        sizes,toCheck,newCheck []int
+        visited []bool
+    )
+    visited=make([]bool,len(network.nodes))
+    for start=range network.nodes {
+        if !visited[start] {
+            visited[start]=true
+            size=1
+            newCheck=[]int{start}
+            for len(newCheck)!=0 {
+                toCheck=newCheck
+                newCheck=[]int{}
+                for _,node=range toCheck {
+                    for _,edge=range append(copyInts(network.succ[node]),network.pred[node]...) {
+                        for _,next=range network.edges[edge] {
+                            if !visited[next] {
+                                visited[next]=true
+                                size+=1
+                                newCheck=append(newCheck,next)
+                            }
+                        }
+                    }
+                }
+            }
+            sizes=append(sizes,size)
+        }
+    }
+    return sizes
+}
@@ -0,0 +1,214 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "strconv"
+    "testing"
+)
+// testStatsSIF is testSIF with a duplicated line, a self-loop (G) and a
+// separate component (H, I).
+const testStatsSIF=testSIF+
+    "E\tactivation\tF\n"+
+    "G\tbinding\tG\n"+
+    "H\tbinding\tI\n"
+func TestStats(t *testing.T) {
+    var (
+        i int
+        stats Stats
+        counts,wants []int
+        names []string
+    )
+    stats=readFixture(t,"network.sif",testStatsSIF,ReadOptions{}).Stats(true)
+    counts=[]int{stats.Nodes,stats.Edges,stats.Interactions,stats.SelfLoops,stats.Duplicates,stats.Components,stats.LargestComponent,stats.Diameter}
+    names=[]string{"nodes","edges","interactions","self-loops","duplicates","components","largest component","diameter"}
+    wants=[]int{10,10,10,1,1,3,7,5}
+    for i=range counts {
+        if counts[i]!=wants[i] {
+            t.Errorf("Stats: got %v %s, want %v",counts[i],names[i],wants[i])
+        }
+    }
+    checkLists(t,"Stats",stats.Names,[]string{"activation","binding","inhibition"})
+    checkLists(t,"Stats",intList(stats.NameCounts),[]string{"7","2","1"})
+    checkLists(t,"Stats",intList(stats.InDegrees),[]string{"2","6","2"})
+    checkLists(t,"Stats",intList(stats.OutDegrees),[]string{"2","6","2"})
+    checkLists(t,"Stats",stats.Sources,[]string{"X","H"})
+    checkLists(t,"Stats",stats.Sinks,[]string{"F","I"})
+    // the diameter is opt-in
+    if readFixture(t,"network.sif",testStatsSIF,ReadOptions{}).Stats(false).Diameter!=-1 {
+        t.Errorf("Stats(false): got diameter %v, want -1",readFixture(t,"network.sif",testStatsSIF,ReadOptions{}).Stats(false).Diameter)
+    }
+}
+func TestWriteStats(t *testing.T) {
+    var (
+        stats Stats
+    )
+    stats=readFixture(t,"network.sif","A\tactivation\tB\nA\tinhibition\tB\nB\tactivation\tC\n",ReadOptions{}).Stats(true)
+    checkFile(t,"WriteStats",func(file string) error {return WriteStats(file,stats)},"stats.txt",
+        "nodes: 3\n"+
+        "edges: 2\n"+
+        "interactions: 3\n"+
+        "self-loops: 0\n"+
+        "duplicates: 0\n"+
+        "sources: 1\n"+
+        "sinks: 1\n"+
+        "components: 1\n"+
+        "largest component: 3\n"+
+        "diameter: 2\n"+
+        "\n"+
+        "interaction names:\n"+
+        "    activation: 2\n"+
+        "    inhibition: 1\n"+
+        "\n"+
+        "in-degrees:\n"+
+        "    0: 1\n"+
+        "    1: 2\n"+
+        "\n"+
+        "out-degrees:\n"+
+        "    0: 1\n"+
+        "    1: 2\n"+
+        "\n"+
+        "sources:\n"+
+        "    A\n"+
+        "\n"+
+        "sinks:\n"+
+        "    C\n",
+    )
+    checkFile(t,"WriteStatsJSON",func(file string) error {return WriteStatsJSON(file,stats)},"stats.json",
+        "{\n"+
+        "    \"nodes\": 3,\n"+
+        "    \"edges\": 2,\n"+
+        "    \"interactions\": 3,\n"+
+        "    \"self_loops\": 0,\n"+
+        "    \"duplicates\": 0,\n"+
+        "    \"components\": 1,\n"+
+        "    \"largest_component\": 3,\n"+
+        "    \"diameter\": 2,\n"+
+        "    \"interaction_names\": [\n"+
+        "        {\n"+
+        "            \"name\": \"activation\",\n"+
+        "            \"count\": 2\n"+
+        "        },\n"+
+        "        {\n"+
+        "            \"name\": \"inhibition\",\n"+
+        "            \"count\": 1\n"+
+        "        }\n"+
+        "    ],\n"+
+        "    \"in_degrees\": [\n"+
+        "        {\n"+
+        "            \"degree\": 0,\n"+
+        "            \"nodes\": 1\n"+
+        "        },\n"+
+        "        {\n"+
+        "            \"degree\": 1,\n"+
+        "            \"nodes\": 2\n"+
+        "        }\n"+
+        "    ],\n"+
+        "    \"out_degrees\": [\n"+
+        "        {\n"+
+        "            \"degree\": 0,\n"+
+        "            \"nodes\": 1\n"+
+        "        },\n"+
+        "        {\n"+
+        "            \"degree\": 1,\n"+
+        "            \"nodes\": 2\n"+
+        "        }\n"+
+        "    ],\n"+
+        "    \"sources\": [\n"+
+        "        \"A\"\n"+
+        "    ],\n"+
+        "    \"sinks\": [\n"+
+        "        \"C\"\n"+
+        "    ]\n"+
+        "}\n",
+    )
+    // without diameter
+    stats.Diameter=-1
+    checkFile(t,"WriteStats",func(file string) error {return WriteStats(file,stats)},"stats.txt",
+        "nodes: 3\n"+
+        "edges: 2\n"+
+        "interactions: 3\n"+
+        "self-loops: 0\n"+
+        "duplicates: 0\n"+
+        "sources: 1\n"+
+        "sinks: 1\n"+
+        "components: 1\n"+
+        "largest component: 3\n"+
+        "\n"+
+        "interaction names:\n"+
+        "    activation: 2\n"+
+        "    inhibition: 1\n"+
+        "\n"+
+        "in-degrees:\n"+
+        "    0: 1\n"+
+        "    1: 2\n"+
+        "\n"+
+        "out-degrees:\n"+
+        "    0: 1\n"+
+        "    1: 2\n"+
+        "\n"+
+        "sources:\n"+
+        "    A\n"+
+        "\n"+
+        "sinks:\n"+
+        "    C\n",
+    )
+    checkFile(t,"WriteStatsJSON",func(file string) error {return WriteStatsJSON(file,stats)},"stats.json",
+        "{\n"+
+        "    \"nodes\": 3,\n"+
+        "    \"edges\": 2,\n"+
+        "    \"interactions\": 3,\n"+
+        "    \"self_loops\": 0,\n"+
+        "    \"duplicates\": 0,\n"+
+        "    \"components\": 1,\n"+
+        "    \"largest_component\": 3,\n"+
+        "    \"interaction_names\": [\n"+
+        "        {\n"+
+        "            \"name\": \"activation\",\n"+
+        "            \"count\": 2\n"+
+        "        },\n"+
+        "        {\n"+
+        "            \"name\": \"inhibition\",\n"+
+        "            \"count\": 1\n"+
+        "        }\n"+
+        "    ],\n"+
+        "    \"in_degrees\": [\n"+
+        "        {\n"+
+        "            \"degree\": 0,\n"+
+        "            \"nodes\": 1\n"+
+        "        },\n"+
+        "        {\n"+
+        "            \"degree\": 1,\n"+
+        "            \"nodes\": 2\n"+
+        "        }\n"+
+        "    ],\n"+
+        "    \"out_degrees\": [\n"+
+        "        {\n"+
+        "            \"degree\": 0,\n"+
+        "            \"nodes\": 1\n"+
+        "        },\n"+
+        "        {\n"+
+        "            \"degree\": 1,\n"+
+        "            \"nodes\": 2\n"+
+        "        }\n"+
+        "    ],\n"+
+        "    \"sources\": [\n"+
+        "        \"A\"\n"+
+        "    ],\n"+
+        "    \"sinks\": [\n"+
+        "        \"C\"\n"+
+        "    ]\n"+
+        "}\n",
+    )
+}
+func intList(ints []int) []string {
+    var (
+        i int
+        list []string
+    )
+    list=[]string{}
+    for _,i=range ints {
+        list=append(list,strconv.Itoa(i))
+    }
+    return list
+}
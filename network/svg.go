@@ -0,0 +1,249 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "errors"
+    "html"
+    "strconv"
+    "strings"
+)
+// Drawing is a layered drawing of a network (see layered), computed once by
+// Draw so as to be written both as SVG and as HTML.
+type Drawing struct {
+    network *Network
+    layout *layout
+}
+// Draw computes the layered drawing of the network.
+func (network *Network) Draw() *Drawing {
+    return &Drawing{network:network,layout:network.layered()}
+}
+// WriteSVG writes the drawing to a standalone SVG file, styled after highlight
+// as WriteDOT does, without requiring Graphviz. Hovering an edge shows its
+// interaction names.
+func (drawing *Drawing) WriteSVG(svgFile string,highlight Highlight) error {
+    var (
+        err error
+    )
+    if len(drawing.network.edges)==0 {
+        err=errors.New("empty before writing")
+    } else {
+        err=writeFile(svgFile,[]byte(strings.Join(drawing.network.svgLines(drawing.layout,highlight),"\n")+"\n"))
+    }
+    return err
+}
+// WriteHTML writes the drawing to a self-contained HTML page embedding the SVG
+// of WriteSVG: the drawing can be panned (drag) and zoomed (mouse wheel),
+// hovering an edge shows its interaction names and clicking a node or an edge
+// highlights the paths going through it, clicking the background clearing the
+// highlighting.
+func (drawing *Drawing) WriteHTML(htmlFile string,highlight Highlight) error {
+    var (
+        err error
+        lines []string
+    )
+    if len(drawing.network.edges)==0 {
+        err=errors.New("empty before writing")
+    } else {
+        lines=append(lines,
+            "<!DOCTYPE html>",
+            "<html>",
+            "<head>",
+            "<meta charset=\"utf-8\">",
+            "<title>pathrider</title>",
+            "<style>",
+            "    html, body { margin: 0; height: 100%; overflow: hidden; font-family: Helvetica, Arial, sans-serif; }",
+            "    svg { display: block; width: 100%; height: 100%; cursor: grab; }",
+            "    .node, .edge { cursor: pointer; }",
+            "    .edge:hover path { stroke-width: 3; }",
+            "    .dim { opacity: 0.15; }",
+            "    #info { position: fixed; top: 8px; left: 8px; padding: 4px 8px; background: rgba(255, 255, 255, 0.9); border: 1px solid #cccccc; font-size: 13px; }",
+            "</style>",
+            "</head>",
+            "<body>",
+            "<div id=\"info\">drag to pan, scroll to zoom, click a node or an edge to highlight its paths</div>",
+        )
+        lines=append(lines,drawing.network.svgLines(drawing.layout,highlight)...)
+        lines=append(lines,
+            "<script>",
+            "(function() {",
+            "    var svg=document.querySelector('svg'),info=document.getElementById('info'),help=info.textContent;",
+            "    var box=svg.viewBox.baseVal,view=[box.x,box.y,box.width,box.height],drag=null,moved=false;",
+            "    var nodes=svg.querySelectorAll('.node'),edges=svg.querySelectorAll('.edge');",
+            "    function point(event) {",
+            "        var p=svg.createSVGPoint();",
+            "        p.x=event.clientX;",
+            "        p.y=event.clientY;",
+            "        return p.matrixTransform(svg.getScreenCTM().inverse());",
+            "    }",
+            "    function update() {",
+            "        svg.setAttribute('viewBox',view.join(' '));",
+            "    }",
+            "    function reach(start,forward) {",
+            "        var seen={},toCheck=[start],node,i,from,to;",
+            "        seen[start]=true;",
+            "        while (toCheck.length!=0) {",
+            "            node=toCheck.pop();",
+            "            for (i=0;i<edges.length;i++) {",
+            "                from=edges[i].getAttribute(forward?'data-source':'data-target');",
+            "                to=edges[i].getAttribute(forward?'data-target':'data-source');",
+            "                if ((from==node) && !seen[to]) {",
+            "                    seen[to]=true;",
+            "                    toCheck.push(to);",
+            "                }",
+            "            }",
+            "        }",
+            "        return seen;",
+            "    }",
+            "    function highlight(down,up,edge) {",
+            "        var i,source,target;",
+            "        for (i=0;i<nodes.length;i++) {",
+            "            nodes[i].classList.toggle('dim',!down[nodes[i].getAttribute('data-node')] && !up[nodes[i].getAttribute('data-node')]);",
+            "        }",
+            "        for (i=0;i<edges.length;i++) {",
+            "            source=edges[i].getAttribute('data-source');",
+            "            target=edges[i].getAttribute('data-target');",
+            "            edges[i].classList.toggle('dim',(edges[i]!=edge) && !(down[source] && down[target]) && !(up[source] && up[target]));",
+            "        }",
+            "    }",
+            "    svg.addEventListener('wheel',function(event) {",
+            "        var p=point(event),scale=(event.deltaY<0)?0.8:1.25;",
+            "        event.preventDefault();",
+            "        view=[p.x-(p.x-view[0])*scale,p.y-(p.y-view[1])*scale,view[2]*scale,view[3]*scale];",
+            "        update();",
+            "    });",
+            "    svg.addEventListener('mousedown',function(event) {",
+            "        drag=point(event);",
+            "        moved=false;",
+            "    });",
+            "    window.addEventListener('mousemove',function(event) {",
+            "        var p;",
+            "        if (drag!=null) {",
+            "            p=point(event);",
+            "            view[0]-=p.x-drag.x;",
+            "            view[1]-=p.y-drag.y;",
+            "            moved=true;",
+            "            update();",
+            "        }",
+            "    });",
+            "    window.addEventListener('mouseup',function() {",
+            "        drag=null;",
+            "    });",
+            "    svg.addEventListener('click',function(event) {",
+            "        var node=event.target.closest('.node'),edge=event.target.closest('.edge'),i;",
+            "        if (moved) {",
+            "            return;",
+            "        } else if (node!=null) {",
+            "            highlight(reach(node.getAttribute('data-node'),true),reach(node.getAttribute('data-node'),false),null);",
+            "        } else if (edge!=null) {",
+            "            highlight(reach(edge.getAttribute('data-target'),true),reach(edge.getAttribute('data-source'),false),edge);",
+            "        } else {",
+            "            for (i=0;i<nodes.length;i++) {",
+            "                nodes[i].classList.remove('dim');",
+            "            }",
+            "            for (i=0;i<edges.length;i++) {",
+            "                edges[i].classList.remove('dim');",
+            "            }",
+            "        }",
+            "    });",
+            "    svg.addEventListener('mouseover',function(event) {",
+            "        var edge=event.target.closest('.edge');",
+            "        info.textContent=(edge!=null)?edge.querySelector('title').textContent:help;",
+            "    });",
+            "})();",
+            "</script>",
+            "</body>",
+            "</html>",
+        )
+        err=writeFile(htmlFile,[]byte(strings.Join(lines,"\n")+"\n"))
+    }
+    return err
+}
+// svgLines returns the SVG of the network drawn after drawing, one element per
+// line: the edges, identified by the IDs of their source and target nodes,
+// then the nodes, identified by their ID.
+func (network *Network) svgLines(drawing *layout,highlight Highlight) []string {
+    var (
+        positive,negative bool
+        node,edge,i int
+        x,y,w,h float64
+        attrs,points,shapes,lines []string
+        sources,targets,seeds,terminals,blackNeighbours []bool
+    )
+    sources=network.nodeSet(highlight.Sources)
+    targets=network.nodeSet(highlight.Targets)
+    seeds=network.nodeSet(highlight.Seeds)
+    terminals=network.nodeSet(highlight.Terminals)
+    blackNeighbours=network.nodeSet(highlight.BlackNeighbours)
+    lines=append(lines,
+        "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\""+svgNumber(drawing.width)+"\" height=\""+svgNumber(drawing.height)+"\" viewBox=\"0 0 "+svgNumber(drawing.width)+" "+svgNumber(drawing.height)+"\" font-family=\"Helvetica, Arial, sans-serif\" font-size=\"12\">",
+        "<defs>",
+        "<marker id=\"arrow\" viewBox=\"0 0 10 10\" refX=\"10\" refY=\"5\" markerWidth=\"7\" markerHeight=\"7\" orient=\"auto\"><path d=\"M0,0 L10,5 L0,10 z\" fill=\"#316ac4\"/></marker>",
+        "<marker id=\"tee\" viewBox=\"0 0 4 12\" refX=\"4\" refY=\"6\" markerWidth=\"4\" markerHeight=\"12\" markerUnits=\"userSpaceOnUse\" orient=\"auto\"><rect width=\"3\" height=\"12\" fill=\"#316ac4\"/></marker>",
+        "<marker id=\"odot\" viewBox=\"0 0 10 10\" refX=\"10\" refY=\"5\" markerWidth=\"7\" markerHeight=\"7\" orient=\"auto\"><circle cx=\"5\" cy=\"5\" r=\"4\" fill=\"white\" stroke=\"#316ac4\" stroke-width=\"1.5\"/></marker>",
+        "<linearGradient id=\"source-target\"><stop offset=\"50%\" stop-color=\"#99ff99\"/><stop offset=\"50%\" stop-color=\"#ff9999\"/></linearGradient>",
+        "</defs>",
+        "<rect width=\"100%\" height=\"100%\" fill=\"white\"/>",
+    )
+    for edge=range network.edges {
+        x,y,w=drawing.x[network.edges[edge][0]],drawing.y[network.edges[edge][0]],drawing.w[network.edges[edge][0]]
+        points=[]string{}
+        if drawing.routes[edge]==nil {
+            points=append(points,"M"+svgNumber(x+w/2)+","+svgNumber(y-6),"C"+svgNumber(x+w/2+30)+","+svgNumber(y-24),svgNumber(x+w/2+30)+","+svgNumber(y+24),svgNumber(x+w/2)+","+svgNumber(y+6))
+        } else {
+            for i=range drawing.routes[edge] {
+                points=append(points,svgNumber(drawing.routes[edge][i][0])+","+svgNumber(drawing.routes[edge][i][1]))
+            }
+            points[0]="M"+points[0]
+            points[1]="L"+points[1]
+        }
+        attrs=[]string{"fill=\"none\"","stroke=\"#316ac4\"","stroke-width=\"1.5\""}
+        positive,negative=highlight.Signs.signs(network.edgeNames[edge])
+        if positive && negative {
+            attrs=append(attrs,"marker-end=\"url(#odot)\"")
+        } else if negative {
+            attrs=append(attrs,"marker-end=\"url(#tee)\"")
+        } else if positive {
+            attrs=append(attrs,"marker-end=\"url(#arrow)\"")
+        } else {
+            attrs=append(attrs,"marker-end=\"url(#arrow)\"","stroke-dasharray=\"5,3\"")
+        }
+        lines=append(lines,
+            "<g class=\"edge\" data-source=\""+strconv.Itoa(network.edges[edge][0])+"\" data-target=\""+strconv.Itoa(network.edges[edge][1])+"\">"+
+            "<title>"+html.EscapeString(network.nodes[network.edges[edge][0]]+" -> "+network.nodes[network.edges[edge][1]]+": "+strings.Join(network.edgeNames[edge],"; "))+"</title>"+
+            "<path d=\""+strings.Join(points," ")+"\" "+strings.Join(attrs," ")+"/></g>",
+        )
+    }
+    for node=range network.nodes {
+        x,y,w,h=drawing.x[node],drawing.y[node],drawing.w[node],layoutNodeHeight
+        attrs=[]string{"stroke=\"#333333\""}
+        if sources[node] && targets[node] {
+            attrs=append(attrs,"fill=\"url(#source-target)\"")
+        } else if sources[node] {
+            attrs=append(attrs,"fill=\"#99ff99\"")
+        } else if targets[node] {
+            attrs=append(attrs,"fill=\"#ff9999\"")
+        } else if seeds[node] {
+            attrs=append(attrs,"fill=\"#ffcc66\"")
+        } else {
+            attrs=append(attrs,"fill=\"#c3d4e8\"")
+        }
+        if blackNeighbours[node] {
+            attrs=append(attrs,"stroke-dasharray=\"4,2\"")
+        }
+        shapes=[]string{}
+        if terminals[node] {
+            shapes=append(shapes,"<rect x=\""+svgNumber(x-w/2-3)+"\" y=\""+svgNumber(y-h/2-3)+"\" width=\""+svgNumber(w+6)+"\" height=\""+svgNumber(h+6)+"\" rx=\"10\" fill=\"none\" stroke=\"#333333\"/>")
+        }
+        shapes=append(shapes,
+            "<rect x=\""+svgNumber(x-w/2)+"\" y=\""+svgNumber(y-h/2)+"\" width=\""+svgNumber(w)+"\" height=\""+svgNumber(h)+"\" rx=\"8\" "+strings.Join(attrs," ")+"/>",
+            "<text x=\""+svgNumber(x)+"\" y=\""+svgNumber(y)+"\" text-anchor=\"middle\" dominant-baseline=\"central\">"+html.EscapeString(network.nodes[node])+"</text>",
+        )
+        lines=append(lines,"<g class=\"node\" data-node=\""+strconv.Itoa(node)+"\"><title>"+html.EscapeString(network.nodes[node])+"</title>"+strings.Join(shapes,"")+"</g>")
+    }
+    lines=append(lines,"</svg>")
+    return lines
+}
+func svgNumber(x float64) string {
+    return strconv.FormatFloat(x,'f',1,64)
+}
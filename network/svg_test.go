@@ -0,0 +1,147 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+func TestLayered(t *testing.T) {
+    var (
+        edge,node int
+        back []bool
+        network *Network
+        drawing *layout
+    )
+    network=readFixture(t,"network.sif","A\tactivation\tB\nB\tactivation\tC\nC\tinhibition\tA\nA\tactivation\tC\nC\tbinding\tC\n",ReadOptions{})
+    back=network.backEdges()
+    for edge=range network.edges {
+        if back[edge]!=((network.nodes[network.edges[edge][0]]=="C") && ((network.nodes[network.edges[edge][1]]=="A") || (network.nodes[network.edges[edge][1]]=="C"))) {
+            t.Errorf("backEdges: %s -> %s: got %v",network.nodes[network.edges[edge][0]],network.nodes[network.edges[edge][1]],back[edge])
+        }
+    }
+    checkLists(t,"longestPathLayers",intList(network.longestPathLayers(back)),[]string{"0","1","2"})
+    drawing=network.layered()
+    for node=range network.nodes {
+        if drawing.y[node]!=layerY(node) {
+            t.Errorf("layered: %s: got y %v, want %v",network.nodes[node],drawing.y[node],layerY(node))
+        }
+    }
+    for edge=range network.edges {
+        if network.isSelfLoop(edge)!=(drawing.routes[edge]==nil) {
+            t.Errorf("layered: %s -> %s: got route %v",network.nodes[network.edges[edge][0]],network.nodes[network.edges[edge][1]],drawing.routes[edge])
+        }
+    }
+    // A -> C spans two layers, so that it goes through one virtual node
+    if len(drawing.routes[network.edgeIDs[[2]int{0,2}]])!=3 {
+        t.Errorf("layered: A -> C: got route %v, want 3 points",drawing.routes[network.edgeIDs[[2]int{0,2}]])
+    }
+}
+func TestCrossings(t *testing.T) {
+    var (
+        i int
+        layers,down [][]int
+        pos []float64
+        tests []struct {
+            layers,down [][]int
+            pos []float64
+            want int
+        }
+    )
+    tests=[]struct {
+        layers,down [][]int
+        pos []float64
+        want int
+    }{
+        {[][]int{{0,1},{2,3}},[][]int{{2},{3},{},{}},[]float64{0,1,0,1},0},
+        {[][]int{{0,1},{2,3}},[][]int{{3},{2},{},{}},[]float64{0,1,0,1},1},
+        // edges sharing an end do not cross
+        {[][]int{{0,1},{2,3}},[][]int{{2,3},{3},{},{}},[]float64{0,1,0,1},0},
+        {[][]int{{0,1,2},{3,4,5}},[][]int{{5},{4},{3},{},{},{}},[]float64{0,1,2,0,1,2},3},
+        // edges sharing a lower end do not cross either
+        {[][]int{{0,1,2},{3,4}},[][]int{{4},{3,4},{3},{},{}},[]float64{0,1,2,0,1},3},
+        // three layers, the crossings of each pair of adjacent layers adding up
+        {[][]int{{0,1},{2,3},{4,5}},[][]int{{3},{2},{5},{4},{},{}},[]float64{0,1,0,1,0,1},2},
+    }
+    for i=range tests {
+        if crossings(tests[i].layers,tests[i].down,tests[i].pos)!=tests[i].want {
+            t.Errorf("crossings(%v): got %d, want %d",tests[i].down,crossings(tests[i].layers,tests[i].down,tests[i].pos),tests[i].want)
+        }
+    }
+    // all the edges between two layers of n nodes: any two upper nodes and any
+    // two lower nodes make one crossing, namely (n*(n-1)/2)^2
+    layers=[][]int{{},{}}
+    down=make([][]int,12)
+    pos=make([]float64,12)
+    for i=0;i<6;i++ {
+        layers[0]=append(layers[0],i)
+        layers[1]=append(layers[1],6+i)
+        pos[i],pos[6+i]=float64(i),float64(i)
+    }
+    for i=0;i<6;i++ {
+        down[i]=layers[1]
+    }
+    if crossings(layers,down,pos)!=225 {
+        t.Errorf("crossings(%v): got %d, want 225",down,crossings(layers,down,pos))
+    }
+}
+func TestWriteSVG(t *testing.T) {
+    var (
+        err error
+        dir string
+        content []byte
+        network *Network
+        highlight Highlight
+    )
+    network=readFixture(t,"network.sif","A\tactivation\tB\nA\tinhibition\tB\nB\tinhibition\tC\nC\tbinding\t\"D<q\"\n",ReadOptions{})
+    highlight=Highlight{Sources:[]string{"A"},Targets:[]string{"C"},Terminals:[]string{"D<q"}}
+    dir=t.TempDir()
+    err=network.Draw().WriteSVG(filepath.Join(dir,"out.svg"),highlight)
+    if err!=nil {
+        t.Fatalf("WriteSVG: %v",err)
+    }
+    content,err=os.ReadFile(filepath.Join(dir,"out.svg"))
+    if err!=nil {
+        t.Fatalf("WriteSVG: %v",err)
+    }
+    checkContains(t,"WriteSVG",string(content),[]string{
+        "<svg xmlns=\"http://www.w3.org/2000/svg\"",
+        "<g class=\"edge\" data-source=\"0\" data-target=\"1\"><title>A -&gt; B: activation; inhibition</title>",
+        "marker-end=\"url(#odot)\"",
+        "marker-end=\"url(#tee)\"",
+        "marker-end=\"url(#arrow)\" stroke-dasharray=\"5,3\"",
+        "<g class=\"node\" data-node=\"0\"><title>A</title>",
+        "fill=\"#99ff99\"",
+        "fill=\"#ff9999\"",
+        "<title>D&lt;q</title><rect",
+        "</svg>\n",
+    })
+    err=network.Draw().WriteHTML(filepath.Join(dir,"out.html"),highlight)
+    if err!=nil {
+        t.Fatalf("WriteHTML: %v",err)
+    }
+    content,err=os.ReadFile(filepath.Join(dir,"out.html"))
+    if err!=nil {
+        t.Fatalf("WriteHTML: %v",err)
+    }
+    checkContains(t,"WriteHTML",string(content),[]string{"<!DOCTYPE html>","<svg xmlns=\"http://www.w3.org/2000/svg\"","<script>","</html>\n"})
+    if New().Draw().WriteSVG(filepath.Join(dir,"empty.svg"),Highlight{})==nil {
+        t.Errorf("WriteSVG: no error for an empty network")
+    }
+    if New().Draw().WriteHTML(filepath.Join(dir,"empty.html"),Highlight{})==nil {
+        t.Errorf("WriteHTML: no error for an empty network")
+    }
+}
+func checkContains(t *testing.T,name,content string,want []string) {
+    var (
+        element string
+    )
+    t.Helper()
+    for _,element=range want {
+        if !strings.Contains(content,element) {
+            t.Errorf("%s: missing %q",name,element)
+        }
+    }
+}
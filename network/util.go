@@ -0,0 +1,139 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+
+// WARNING The functions in the present file do not fully handle exceptions and
+// errors. Instead, they assume that such handling is performed upstream by the
+// methods of Network, which is why they are not exported.
+
+package network
+import (
+    "os"
+)
+// openFile opens file for reading, "-" standing for the standard input.
+func openFile(file string) (*os.File,error) {
+    var (
+        err error
+        f *os.File
+    )
+    if file=="-" {
+        f=os.Stdin
+    } else {
+        f,err=os.Open(file)
+    }
+    return f,err
+}
+// createFile creates file for writing, "-" standing for the standard output.
+func createFile(file string) (*os.File,error) {
+    var (
+        err error
+        f *os.File
+    )
+    if file=="-" {
+        f=os.Stdout
+    } else {
+        f,err=os.Create(file)
+    }
+    return f,err
+}
+// closeFile closes a file opened by openFile or createFile, leaving the
+// standard input and output open.
+func closeFile(file *os.File) {
+    if (file!=os.Stdin) && (file!=os.Stdout) {
+        file.Close()
+    }
+}
+// writeFile is like os.WriteFile but uses createFile.
+func writeFile(file string,data []byte) error {
+    var (
+        err error
+        f *os.File
+    )
+    f,err=createFile(file)
+    defer closeFile(f)
+    if err==nil {
+        _,err=f.Write(data)
+    }
+    return err
+}
+func copyList(list []string) []string {
+    var (
+        y []string
+    )
+    y=make([]string,len(list))
+    copy(y,list)
+    return y
+}
+func copyInts(list []int) []int {
+    var (
+        y []int
+    )
+    y=make([]int,len(list))
+    copy(y,list)
+    return y
+}
+func intsEq(list1,list2 []int) bool {
+    var (
+        eq bool
+        i int
+    )
+    eq=true
+    if len(list1)!=len(list2) {
+        eq=false
+    } else {
+        for i=range list1 {
+            if list1[i]!=list2[i] {
+                eq=false
+                break
+            }
+        }
+    }
+    return eq
+}
+func isInList(list []string,thatElement string) bool {
+    var (
+        found bool
+        element string
+    )
+    found=false
+    for _,element=range list {
+        if element==thatElement {
+            found=true
+            break
+        }
+    }
+    return found
+}
+func intsShare(list1,list2 []int) bool {
+    var (
+        found bool
+        element1,element2 int
+    )
+    found=false
+    for _,element1=range list1 {
+        for _,element2=range list2 {
+            if element1==element2 {
+                found=true
+                break
+            }
+        }
+        if found {
+            break
+        }
+    }
+    return found
+}
+func isInInts(list []int,thatElement int) bool {
+    var (
+        found bool
+        element int
+    )
+    found=false
+    for _,element=range list {
+        if element==thatElement {
+            found=true
+            break
+        }
+    }
+    return found
+}
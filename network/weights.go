@@ -0,0 +1,367 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "container/heap"
+    "encoding/csv"
+    "errors"
+    "math"
+    "os"
+    "strconv"
+)
+// Weighted reports whether the interactions of the network carry weights.
+func (network *Network) Weighted() bool {
+    return network.weighted
+}
+// EdgeWeights returns the weights of the interaction names of the edge
+// source -> target, in the order of EdgeNames.
+func (network *Network) EdgeWeights(source,target string) []float64 {
+    var (
+        found bool
+        edge int
+        weights []float64
+    )
+    edge,found=network.edgeID(source,target)
+    if found {
+        weights=append(weights,network.weights[edge]...)
+    }
+    return weights
+}
+// ReadWeights reads the weights of the interactions of the network from a TSV
+// file, either as "source interaction target weight" lines or as
+// "source target weight" lines, the latter weighting all the interaction names
+// of the edge. It makes the network weighted, the interactions which are not
+// listed keeping their weight. The nodes are mapped (see Map).
+func (network *Network) ReadWeights(weightFile string) error {
+    var (
+        err error
+        found bool
+        i,edge int
+        weight float64
+        name string
+        line []string
+        lines [][]string
+        file *os.File
+        reader *csv.Reader
+    )
+    file,err=openFile(weightFile)
+    defer closeFile(file)
+    if err==nil {
+        reader=csv.NewReader(file)
+        reader.Comma='\t'
+        reader.Comment='#'
+        reader.FieldsPerRecord=-1
+        reader.LazyQuotes=false
+        reader.TrimLeadingSpace=true
+        reader.ReuseRecord=true
+        lines,err=reader.ReadAll()
+        if err==nil {
+            for _,line=range lines {
+                if (len(line)!=3) && (len(line)!=4) {
+                    err=errors.New(line[0]+": expecting source target weight or source interaction target weight")
+                    break
+                }
+                line[0]=network.mapName(line[0])
+                line[len(line)-2]=network.mapName(line[len(line)-2])
+                weight,err=parseWeight(line[len(line)-1])
+                if err!=nil {
+                    err=errors.New(line[0]+" -> "+line[len(line)-2]+": "+err.Error())
+                    break
+                }
+                edge,found=network.edgeID(line[0],line[len(line)-2])
+                if found && (len(line)==4) {
+                    found=isInList(network.edgeNames[edge],line[1])
+                }
+                if !found {
+                    err=errors.New(line[0]+" -> "+line[len(line)-2]+": edge not in network")
+                    break
+                }
+                for i,name=range network.edgeNames[edge] {
+                    if (len(line)==3) || (name==line[1]) {
+                        network.weights[edge][i]=weight
+                    }
+                }
+            }
+            if err==nil {
+                if len(lines)==0 {
+                    err=errors.New("empty after reading")
+                } else {
+                    network.weighted=true
+                }
+            }
+        }
+    }
+    return err
+}
+// UseConfidence makes the weights of the network confidence scores, between 0
+// (excluded) and 1, rather than lengths: an interaction then has the length
+// -log(score) in shortest path computations, so that the shortest paths are
+// the most confident ones. Interactions without weight have score 1.
+func (network *Network) UseConfidence() error {
+    var (
+        err error
+        i,edge int
+        weight float64
+    )
+    for edge=range network.edges {
+        for i,weight=range network.weights[edge] {
+            if weight>1 {
+                err=errors.New(network.nodes[network.edges[edge][0]]+" "+network.edgeNames[edge][i]+" "+network.nodes[network.edges[edge][1]]+": "+formatWeight(weight)+": confidence score must be between 0 and 1")
+                break
+            }
+        }
+        if err!=nil {
+            break
+        }
+    }
+    if err==nil {
+        network.confidence=true
+    }
+    return err
+}
+// FilterWeights returns the network without the interactions weighing less
+// than minWeight, namely without the least confident ones if the weights are
+// confidence scores (see UseConfidence), and without the edges left without
+// interactions.
+func (network *Network) FilterWeights(minWeight float64) (*Network,error) {
+    var (
+        err error
+        i,edge int
+        weight float64
+        newNetwork *Network
+    )
+    newNetwork=New()
+    for edge=range network.edges {
+        for i,weight=range network.weights[edge] {
+            if weight>=minWeight {
+                newNetwork.copyName(network,edge,i)
+            }
+        }
+    }
+    if len(newNetwork.edges)==0 {
+        err=errors.New("network empty after filtering weights")
+    }
+    return newNetwork,err
+}
+func parseWeight(field string) (float64,error) {
+    var (
+        err error
+        weight float64
+    )
+    weight,err=strconv.ParseFloat(field,64)
+    if (err!=nil) || !(weight>0) || math.IsInf(weight,1) {
+        err=errors.New(field+": weight must be a positive number")
+    }
+    return weight,err
+}
+func formatWeight(weight float64) string {
+    return strconv.FormatFloat(weight,'g',-1,64)
+}
+// edgeLength returns the length of an edge in shortest path computations,
+// namely the lowest weight of its interactions, or the lowest -log(score) if
+// the weights are confidence scores.
+func (network *Network) edgeLength(edge int) float64 {
+    var (
+        weight,length float64
+    )
+    length=math.Inf(1)
+    for _,weight=range network.weights[edge] {
+        if network.confidence {
+            weight=math.Log(1/weight)
+        }
+        length=math.Min(length,weight)
+    }
+    return length
+}
+// pathLength returns the length of a path given as edge IDs: its number of
+// edges if the network is unweighted, the sum of the lengths of its edges
+// otherwise.
+func (network *Network) pathLength(edgePath []int) float64 {
+    var (
+        edge int
+        length float64
+    )
+    if !network.weighted {
+        length=float64(len(edgePath))
+    } else {
+        for _,edge=range edgePath {
+            length+=network.edgeLength(edge)
+        }
+    }
+    return length
+}
+// getWeightedLayers is the weighted counterpart of getLayers (Dijkstra's
+// algorithm): it returns for each node the edges lying on the shortest paths
+// from seed to it, ignoring self-loops.
+func (network *Network) getWeightedLayers(seed int) [][]int {
+    var (
+        node,edge,esucc int
+        distances []float64
+        layerPred [][]int
+        done []bool
+        queue *nodeQueue
+    )
+    layerPred=make([][]int,len(network.nodes))
+    distances=make([]float64,len(network.nodes))
+    done=make([]bool,len(network.nodes))
+    for node=range distances {
+        distances[node]=math.Inf(1)
+    }
+    queue=&nodeQueue{}
+    for _,esucc=range network.succ[seed] {
+        if !network.isSelfLoop(esucc) {
+            network.relax(esucc,0,distances,layerPred,queue)
+        }
+    }
+    for queue.Len()!=0 {
+        node=heap.Pop(queue).(queuedNode).node
+        if !done[node] {
+            done[node]=true
+            if node!=seed {
+                for _,edge=range network.succ[node] {
+                    if !network.isSelfLoop(edge) && !done[network.edges[edge][1]] {
+                        network.relax(edge,distances[node],distances,layerPred,queue)
+                    }
+                }
+            }
+        }
+    }
+    return layerPred
+}
+// relax updates the distance of the target of edge reached from a node at
+// distance from.
+func (network *Network) relax(edge int,from float64,distances []float64,layerPred [][]int,queue *nodeQueue) {
+    var (
+        next int
+        length float64
+    )
+    next=network.edges[edge][1]
+    length=from+network.edgeLength(edge)
+    if sameLength(length,distances[next]) {
+        layerPred[next]=append(layerPred[next],edge)
+    } else if length<distances[next] {
+        distances[next]=length
+        layerPred[next]=[]int{edge}
+        heap.Push(queue,queuedNode{node:next,distance:length})
+    }
+}
+// weightedShortestPaths is the weighted counterpart of shortestPaths: it
+// gathers the edges lying on the shortest paths from source to target by
+// walking back layerPred from target, without going past source.
+func (network *Network) weightedShortestPaths(source,target int,layerPred [][]int) []int {
+    var (
+        found bool
+        edge,epred int
+        newCheck,toCheck,shortest []int
+        inShortest []bool
+    )
+    inShortest=make([]bool,len(network.edges))
+    newCheck=copyInts(layerPred[target])
+    for _,edge=range newCheck {
+        inShortest[edge]=true
+        shortest=append(shortest,edge)
+    }
+    for len(newCheck)!=0 {
+        toCheck=newCheck
+        newCheck=[]int{}
+        for _,edge=range toCheck {
+            if network.edges[edge][0]==source {
+                found=true
+            } else {
+                for _,epred=range layerPred[network.edges[edge][0]] {
+                    if !inShortest[epred] {
+                        inShortest[epred]=true
+                        shortest=append(shortest,epred)
+                        newCheck=append(newCheck,epred)
+                    }
+                }
+            }
+        }
+    }
+    if !found {
+        shortest=[]int{}
+    }
+    return shortest
+}
+// dijkstraPath is the weighted counterpart of bfsPath.
+func (network *Network) dijkstraPath(from,to int,removedNodes []bool,removedEdges map[int]bool) []int {
+    var (
+        found,started bool
+        node,edge,next int
+        length float64
+        path,parent []int
+        distances []float64
+        done []bool
+        current queuedNode
+        queue *nodeQueue
+    )
+    parent=make([]int,len(network.nodes))
+    distances=make([]float64,len(network.nodes))
+    done=make([]bool,len(network.nodes))
+    for node=range distances {
+        distances[node]=math.Inf(1)
+    }
+    queue=&nodeQueue{}
+    heap.Push(queue,queuedNode{node:from,distance:0})
+    for (queue.Len()!=0) && !found {
+        current=heap.Pop(queue).(queuedNode)
+        if started && (current.node==to) {
+            found=true
+        } else if !started || (!done[current.node] && (current.distance<=distances[current.node])) {
+            started=true
+            done[current.node]=(current.node!=to)
+            for _,edge=range network.succ[current.node] {
+                next=network.edges[edge][1]
+                if !removedEdges[edge] && ((next==to) || (!removedNodes[next] && !done[next])) {
+                    length=current.distance+network.edgeLength(edge)
+                    if length<distances[next] {
+                        distances[next]=length
+                        parent[next]=edge
+                        heap.Push(queue,queuedNode{node:next,distance:length})
+                    }
+                }
+            }
+        }
+    }
+    if found {
+        node=to
+        for (len(path)==0) || (node!=from) {
+            path=append([]int{parent[node]},path...)
+            node=network.edges[parent[node]][0]
+        }
+    }
+    return path
+}
+// sameLength tells whether two finite path lengths are equal, up to rounding
+// errors.
+func sameLength(length1,length2 float64) bool {
+    return !math.IsInf(length1,1) && !math.IsInf(length2,1) && (math.Abs(length1-length2)<=1e-9*math.Max(length1,length2))
+}
+// nodeQueue is a priority queue of nodes ordered by distance, for
+// container/heap.
+type queuedNode struct {
+    node int
+    distance float64
+}
+type nodeQueue []queuedNode
+func (queue nodeQueue) Len() int {
+    return len(queue)
+}
+func (queue nodeQueue) Less(i,j int) bool {
+    return queue[i].distance<queue[j].distance
+}
+func (queue nodeQueue) Swap(i,j int) {
+    queue[i],queue[j]=queue[j],queue[i]
+}
+func (queue *nodeQueue) Push(x interface{}) {
+    *queue=append(*queue,x.(queuedNode))
+}
+func (queue *nodeQueue) Pop() interface{} {
+    var (
+        last queuedNode
+    )
+    last=(*queue)[len(*queue)-1]
+    *queue=(*queue)[:len(*queue)-1]
+    return last
+}
@@ -0,0 +1,118 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package network
+import (
+    "strings"
+    "testing"
+)
+func TestReadWeights(t *testing.T) {
+    var (
+        i int
+        err error
+        network *Network
+        tests []struct {
+            content string
+            lines []string
+            err string
+        }
+    )
+    tests=[]struct {
+        content string
+        lines []string
+        err string
+    }{
+        // a whole edge, then a single interaction name
+        {"A\tB\t2\n",[]string{"A activation B 2","A inhibition B 2","B activation C 1"},""},
+        {"A\tinhibition\tB\t0.5\n",[]string{"A activation B 1","A inhibition B 0.5","B activation C 1"},""},
+        {"# source\ttarget\tweight\nA\tB\t2\nA\tinhibition\tB\t0.5\n",[]string{"A activation B 2","A inhibition B 0.5","B activation C 1"},""},
+        {"A\tC\t2\n",nil,"A -> C: edge not in network"},
+        {"A\tactivation\tC\t2\n",nil,"A -> C: edge not in network"},
+        {"A\tbinding\tB\t2\n",nil,"A -> B: edge not in network"},
+        {"A\tB\t0\n",nil,"A -> B: 0: weight must be a positive number"},
+        {"A\tB\n",nil,"A: expecting source target weight"},
+        {"",nil,"empty after reading"},
+    }
+    for i=range tests {
+        network=readFixture(t,"network.sif","A\tactivation\tB\nA\tinhibition\tB\nB\tactivation\tC\n",ReadOptions{})
+        err=network.ReadWeights(writeFixture(t,"weights.tsv",tests[i].content))
+        checkError(t,"ReadWeights("+strings.ReplaceAll(tests[i].content,"\n","\\n")+")",err,tests[i].err)
+        if err==nil {
+            checkLists(t,"ReadWeights",sifLines(network),tests[i].lines)
+            if !network.Weighted() {
+                t.Errorf("ReadWeights: network not weighted")
+            }
+        }
+    }
+}
+func TestFilterWeights(t *testing.T) {
+    var (
+        i int
+        err error
+        network,filtered *Network
+        tests []struct {
+            minWeight float64
+            lines []string
+            err string
+        }
+    )
+    network=readFixture(t,"weighted.sif",testWeightedSIF,ReadOptions{Weighted:true})
+    tests=[]struct {
+        minWeight float64
+        lines []string
+        err string
+    }{
+        {1,[]string{"A activation B 1","B activation C 1","A activation C 3","C activation D 1","A inhibition D 5"},""},
+        {3,[]string{"A activation C 3","A inhibition D 5"},""},
+        {6,nil,"network empty after filtering weights"},
+    }
+    for i=range tests {
+        filtered,err=network.FilterWeights(tests[i].minWeight)
+        checkError(t,"FilterWeights("+formatWeight(tests[i].minWeight)+")",err,tests[i].err)
+        if err==nil {
+            checkLists(t,"FilterWeights("+formatWeight(tests[i].minWeight)+")",sifLines(filtered),tests[i].lines)
+        }
+    }
+}
+func TestEdgeWeights(t *testing.T) {
+    var (
+        i int
+        weights []float64
+        network *Network
+    )
+    network=readFixture(t,"network.sif","A\tactivation\tB\t2\nA\tinhibition\tB\t0.5\n",ReadOptions{Weighted:true})
+    weights=network.EdgeWeights("A","B")
+    if len(weights)!=2 {
+        t.Errorf("EdgeWeights: got %v, want [2 0.5]",weights)
+    } else {
+        for i=range weights {
+            if formatWeight(weights[i])!=[]string{"2","0.5"}[i] {
+                t.Errorf("EdgeWeights: got %v, want [2 0.5]",weights)
+            }
+        }
+    }
+    if network.EdgeWeights("B","A")!=nil {
+        t.Errorf("EdgeWeights: got %v for a missing edge, want nil",network.EdgeWeights("B","A"))
+    }
+    if readFixture(t,"network.sif",testSIF,ReadOptions{}).Weighted() {
+        t.Errorf("Weighted: unweighted network reported as weighted")
+    }
+}
+func TestUseConfidence(t *testing.T) {
+    var (
+        err error
+        network,filtered *Network
+    )
+    // A -> B -> C (0.9 * 0.9) is more confident than A -> C (0.5)
+    network=readFixture(t,"network.sif","A\tactivation\tB\t0.9\nB\tactivation\tC\t0.9\nA\tactivation\tC\t0.5\n",ReadOptions{Weighted:true})
+    checkLists(t,"Shortest",edgeList(network.Shortest([]string{"A"},[]string{"C"})),[]string{"A C"})
+    err=network.UseConfidence()
+    checkError(t,"UseConfidence",err,"")
+    checkSets(t,"Shortest",edgeList(network.Shortest([]string{"A"},[]string{"C"})),[]string{"A B","B C"})
+    // the least confident interactions are filtered out
+    filtered,err=network.FilterWeights(0.6)
+    checkError(t,"FilterWeights",err,"")
+    checkLists(t,"FilterWeights",sifLines(filtered),[]string{"A activation B 0.9","B activation C 0.9"})
+    err=readFixture(t,"network.sif","A\tactivation\tB\t0.9\nB\tactivation\tC\t2\n",ReadOptions{Weighted:true}).UseConfidence()
+    checkError(t,"UseConfidence",err,"B activation C: 2: confidence score must be between 0 and 1")
+}
@@ -5,21 +5,27 @@ package main
 import (
     "flag"
     "fmt"
+    "github.com/arnaudporet/pathrider/network"
     "math"
     "os"
     "path/filepath"
+    "strconv"
     "strings"
 )
 func Connect() {
     var (
         err1,err2 error
-        help,usage,getShortest bool
-        outFile,outFilePath,outFileBase,blackFile string
-        args,nodes,sources,targets,blackNodes,selfLooped []string
-        edges,forward,backward,intersect,noSelfLoop,allShortest [][]string
-        nodeSucc,nodePred map[string][]string
-        edgeNames map[string]map[string][]string
-        edgeSucc,edgePred map[string]map[string][][]string
+        help,usage,weighted,confidence,getProvenance,getComponents,getReachability,writeJSON,getShortest,skipComments,writeDOT,writeSVG,listPaths bool
+        k,maxLength,maxCount,minWeight float64
+        outFile,interactionAttribute,mapFile,mapOutput,pathFile,reachFile,blackFile,blackEdgeFile,weightFile,pathSign,viaFile,viaMode,signMapFile,signFile string
+        args,sources,targets,waypoints,blackNeighbours []string
+        net,intersect,positive,negative,allShortest,kShortest *network.Network
+        mapping *network.Mapping
+        kPaths []network.Path
+        reaches []network.Reachability
+        signMap network.SignMap
+        highlight network.Highlight
+        includes,excludes StringList
         flagSet *flag.FlagSet
     )
     flagSet=flag.NewFlagSet("",flag.ContinueOnError)
@@ -28,15 +34,54 @@ func Connect() {
     flagSet.BoolVar(&help,"h",false,"")
     flagSet.BoolVar(&usage,"usage",false,"")
     flagSet.BoolVar(&usage,"u",false,"")
+    flagSet.BoolVar(&quiet,"quiet",false,"")
+    flagSet.BoolVar(&quiet,"q",false,"")
+    flagSet.BoolVar(&verbose,"verbose",false,"")
+    flagSet.BoolVar(&verbose,"v",false,"")
     flagSet.BoolVar(&getShortest,"shortest",false,"")
     flagSet.BoolVar(&getShortest,"s",false,"")
+    flagSet.Float64Var(&k,"kshortest",math.NaN(),"")
+    flagSet.Float64Var(&k,"k",math.NaN(),"")
+    flagSet.Float64Var(&maxLength,"max-length",math.NaN(),"")
+    flagSet.Float64Var(&maxLength,"m",math.NaN(),"")
+    flagSet.BoolVar(&listPaths,"list",false,"")
+    flagSet.BoolVar(&listPaths,"l",false,"")
+    flagSet.StringVar(&pathSign,"sign","","")
+    flagSet.Float64Var(&maxCount,"number",1000,"")
+    flagSet.Float64Var(&maxCount,"n",1000,"")
+    flagSet.StringVar(&viaFile,"via","","")
+    flagSet.StringVar(&viaMode,"via-mode","any","")
+    flagSet.StringVar(&signMapFile,"signs","","")
     flagSet.StringVar(&outFile,"out","out.sif","")
     flagSet.StringVar(&outFile,"o","out.sif","")
     flagSet.StringVar(&blackFile,"blacklist","","")
     flagSet.StringVar(&blackFile,"b","","")
+    flagSet.StringVar(&blackEdgeFile,"blacklist-edges","","")
+    flagSet.StringVar(&blackEdgeFile,"e","","")
+    flagSet.BoolVar(&weighted,"weighted",false,"")
+    flagSet.BoolVar(&weighted,"w",false,"")
+    flagSet.StringVar(&weightFile,"weight-file","","")
+    flagSet.BoolVar(&confidence,"confidence",false,"")
+    flagSet.Float64Var(&minWeight,"min-weight",math.NaN(),"")
+    flagSet.Var(&includes,"include-interaction","")
+    flagSet.Var(&excludes,"exclude-interaction","")
+    flagSet.BoolVar(&getProvenance,"provenance",false,"")
+    flagSet.BoolVar(&getProvenance,"p",false,"")
+    flagSet.BoolVar(&getComponents,"scc",false,"")
+    flagSet.BoolVar(&getReachability,"reachability",false,"")
+    flagSet.BoolVar(&getReachability,"r",false,"")
+    flagSet.BoolVar(&writeJSON,"json",false,"")
+    flagSet.StringVar(&interactionAttribute,"interaction-attribute","","")
+    flagSet.StringVar(&mapFile,"map","","")
+    flagSet.StringVar(&mapOutput,"map-output","","")
+    flagSet.BoolVar(&skipComments,"comments",false,"")
+    flagSet.BoolVar(&skipComments,"c",false,"")
+    flagSet.BoolVar(&writeDOT,"graphviz",false,"")
+    flagSet.BoolVar(&writeDOT,"g",false,"")
+    flagSet.BoolVar(&writeSVG,"svg",false,"")
     err1=flagSet.Parse(os.Args[2:])
     if err1!=nil {
-        fmt.Println("Error: pathrider connect: "+err1.Error())
+        Error(ExitUsage,"pathrider connect: "+err1.Error())
     } else if help {
         fmt.Println(strings.Join([]string{
             "",
@@ -48,7 +93,9 @@ func Connect() {
             "Usage: pathrider connect [options] <networkFile> <sourceFile> <targetFile>",
             "",
             "Positional arguments:",
-            "    * <networkFile>: the network encoded in a SIF file",
+            "    * <networkFile>: the network encoded in a SIF, KGML or GraphML file, several",
+            "                     files separated by commas being merged into one",
+            "                     network",
             "    * <sourceFile>: the source nodes listed in a file (one node per line)",
             "    * <targetFile>: the target nodes listed in a file (one node per line)",
             "    * if sources = targets then provide the same node list twice",
@@ -56,11 +103,115 @@ func Connect() {
             "Options:",
             "    * -s/-shortest: also find the shortest connecting paths (default: not used",
             "                    by default)",
+            "    * -m/-max-length <int>: only keep the edges lying on at least one connecting",
+            "                            walk (a path possibly visiting a node twice)",
+            "                            having at most the given number of edges",
+            "                            (default: not used by default)",
+            "    * -k/-kshortest <int>: also find the k shortest connecting paths (simple",
+            "                           paths) for each source/target pair (default: not",
+            "                           used by default)",
+            "    * -l/-list: also list the k shortest connecting paths one by one (requires",
+            "                -k/-kshortest) (default: not used by default)",
+            "    * -via <file>: only keep the connecting paths passing through the waypoints",
+            "                   listed in a file (one node per line) (default: not used",
+            "                   by default)",
+            "    * -via-mode <mode>: go through at least one waypoint (any) or through all",
+            "                        the waypoints in the order of the file (all)",
+            "                        (default: any)",
+            "    * -sign <sign>: only keep the connecting paths whose overall sign, namely the",
+            "                    product of the signs of their edges, is positive",
+            "                    (positive) or negative (negative), or keep them all but",
+            "                    still sign them (any), the paths going through unsigned",
+            "                    edges having no sign (default: not used by default)",
+            "    * -n/-number <int>: the maximal number of simple connecting paths per",
+            "                        source/target pair signed one by one, beyond which",
+            "                        -sign fails (default: 1000)",
+            "    * -signs <file>: a TSV file mapping interaction names (first column) to",
+            "                     signs (second column: +, - or 0), overriding the default",
+            "                     signs (default: activation and expression are positive,",
+            "                     inhibition and repression are negative, the other",
+            "                     interactions are unsigned)",
+            "    * -w/-weighted: the last column of the network file gives the weight of",
+            "                    the interactions (source interaction target weight),",
+            "                    weights being positive numbers, preserved in the output",
+            "                    files, the length of a path (for -s/-shortest and",
+            "                    -k/-kshortest) being the sum of the weights of its",
+            "                    edges (default: not used by default)",
+            "    * -weight-file <file>: read the weights of the interactions from a TSV",
+            "                           file (source interaction target weight, or source",
+            "                           target weight for all the interactions of an",
+            "                           edge) (default: not used by default)",
+            "    * -confidence: the weights are confidence scores between 0 and 1 (e.g.",
+            "                   STRING or OmniPath scores) rather than lengths, the",
+            "                   length of an interaction being -log(score) so that the",
+            "                   shortest paths are the most confident ones (requires",
+            "                   -w/-weighted or -weight-file) (default: not used by",
+            "                   default)",
+            "    * -min-weight <float>: do not consider the interactions weighing less than",
+            "                           the given weight, namely the least confident ones",
+            "                           with -confidence, interactions without weight",
+            "                           weighing 1 (default: not used by default)",
+            "    * -include-interaction <pattern>: only consider the interactions whose name",
+            "                                      matches pattern, namely a literal name, a",
+            "                                      glob (e.g. activation_*), a regular",
+            "                                      expression between slashes (e.g.",
+            "                                      /^(in|ac)/) or @file for the patterns",
+            "                                      listed in file (one pattern per line),",
+            "                                      can be given several times (default: not",
+            "                                      used by default)",
+            "    * -exclude-interaction <pattern>: do not consider the interactions whose",
+            "                                      name matches pattern, as above (default:",
+            "                                      not used by default)",
             "    * -b/-blacklist <file>: a file containing a list of nodes to be blacklisted",
             "                            (one node per line), the paths containing such nodes",
             "                            will not be considered (default: not used by",
             "                            default)",
-            "    * -o/-out <file>: the output SIF file (default: out.sif)",
+            "    * -e/-blacklist-edges <file>: a file containing a list of edges to be",
+            "                                  blacklisted, either as SIF lines (source",
+            "                                  interaction target) or as source/target",
+            "                                  pairs (source target, for all the",
+            "                                  interactions of the edge), the paths",
+            "                                  containing such edges will not be",
+            "                                  considered (default: not used by default)",
+            "    * -p/-provenance: also write, for each output edge and node, the",
+            "                      source/target pairs (source->target) whose connecting",
+            "                      paths it lies on (default: not used by default)",
+            "    * -scc: also write the strongly connected components of the output paths",
+            "            and their condensation (default: not used by default)",
+            "    * -r/-reachability: also write, for each source/target pair, whether the",
+            "                        source node reaches the target node, the length and",
+            "                        the number of the shortest paths, and the number of",
+            "                        intermediate nodes lying on them (default: not used",
+            "                        by default)",
+            "    * -json: also write the reachability report in JSON (requires",
+            "             -r/-reachability) (default: not used by default)",
+            "    * -interaction-attribute <name>: the edge attribute giving the interaction",
+            "                                     names in GraphML network files (default:",
+            "                                     interaction)",
+            "    * -map <file>: a TSV synonym table (one namespace per column, synonyms",
+            "                   separated by \"|\") mapping the identifiers of the",
+            "                   network and of the node files to node names (default:",
+            "                   not used by default)",
+            "    * -map-output <namespace>: the namespace (column) of -map naming the",
+            "                               nodes, in the output files too (default:",
+            "                               the first column)",
+            "    * -c/-comments: skip the lines of the network file starting with \"#\"",
+            "                    (default: not used by default)",
+            "    * -g/-graphviz: also write the output paths in the DOT file format of",
+            "                    Graphviz, highlighting the source and target nodes",
+            "                    (default: not used by default)",
+            "    * -svg: also draw the output paths in a standalone SVG file and in an",
+            "            interactive HTML page, with a layered layout not requiring",
+            "            Graphviz (default: not used by default)",
+            "    * -o/-out <file>: the output SIF file, or GraphML (.graphml),",
+            "                      cytoscape.js (.json) or CX2 (.cx2) file according to",
+            "                      its extension, the last two being annotated for",
+            "                      Cytoscape (node roles, distances, shortest paths)",
+            "                      (default: out.sif)",
+            "    * -q/-quiet: do not print progress messages (default: not used by",
+            "                 default)",
+            "    * -v/-verbose: also print the size of the network and of the results",
+            "                   (default: not used by default)",
             "    * -u/-usage: print usage only",
             "    * -h/-help: print help",
             "",
@@ -69,12 +220,72 @@ func Connect() {
             "               the target nodes in the network",
             "    * out-shortest.sif: a SIF file encoding only the shortest connecting paths",
             "                        (requires -s/-shortest)",
+            "    * out-signs.tsv: a TSV file giving the overall signs of the connecting paths",
+            "                     each edge of out.sif lies on, unknown if they all go",
+            "                     through unsigned edges (requires -sign)",
+            "    * out-k<k>.sif: a SIF file encoding only the k shortest connecting paths",
+            "                    (requires -k/-kshortest)",
+            "    * out-k<k>.tsv: a TSV file listing the k shortest connecting paths, one path",
+            "                    per line, as pathrider paths does (requires -l/-list)",
+            "    * out-provenance.tsv: a TSV file giving, for each edge of out.sif, the",
+            "                          source/target pairs whose connecting paths it lies",
+            "                          on and their number (requires -p/-provenance)",
+            "    * out-provenance-nodes.tsv: the same for the nodes of out.sif (requires",
+            "                                -p/-provenance)",
+            "    * out-components.tsv: a TSV file giving, for each node of out.sif, its",
+            "                          strongly connected component and the size of this",
+            "                          component (requires -scc)",
+            "    * out-condensed.sif: a SIF file encoding the condensation of out.sif,",
+            "                         namely its strongly connected components linked by",
+            "                         their interactions, the cyclic components being",
+            "                         named scc1, scc2, ... (requires -scc)",
+            "    * out-reachability.tsv: a TSV file giving, for each source/target pair,",
+            "                            whether the source node reaches the target node",
+            "                            (reachable), the length (length) and the number",
+            "                            (paths) of the shortest paths, and the number of",
+            "                            intermediate nodes lying on them (intermediates)",
+            "                            (requires -r/-reachability)",
+            "    * out-reachability.json: the same in JSON (requires -json)",
+            "    * out.dot, out-shortest.dot, out-k<k>.dot: the same paths in the DOT file",
+            "                                               format, to be rendered with",
+            "                                               Graphviz (e.g. dot -Tsvg)",
+            "                                               (requires -g/-graphviz)",
+            "    * out.svg, out-shortest.svg, out-k<k>.svg: the same paths drawn with a",
+            "                                               layered layout (requires",
+            "                                               -svg)",
+            "    * out.html, out-shortest.html, out-k<k>.html: the same drawings in",
+            "                                                  interactive HTML pages",
+            "                                                  (requires -svg)",
+            "    * out-mapping.tsv: a TSV file listing the identifiers kept as is because",
+            "                       they are ambiguous or unmapped (requires -map)",
             "",
             "Cautions:",
-            "    * the network must be in the SIF file format (see the readme file of",
-            "      pathrider)",
+            "    * the network must be in the SIF, KGML or GraphML file format (see the",
+            "      readme file of pathrider)",
+            "    * lines listing several targets (source interaction target1 target2 ...)",
+            "      are expanded into one edge per target",
+            "    * lines made of a single node add this node without any edge",
             "    * edge duplicates are automatically removed",
             "    * edges are assumed to be directed",
+            "    * - stands for the standard input in place of an input file (once at",
+            "      most) and for the standard output with -o/-out",
+            "    * an interaction name joining several interactions with commas (e.g.",
+            "      activation_PPrel,phosphorylation_PPrel) is considered if any of its",
+            "      parts is",
+            "    * a path going through an unsigned interaction has no sign",
+            "    * with -sign, the connecting paths are signed one by one as simple paths",
+            "      (not visiting a node twice), which also go through the waypoints",
+            "      (-via) and have at most -m/-max-length edges",
+            "    * with -sign, the shortest connecting paths are computed among the kept",
+            "      connecting paths",
+            "",
+            "Exit status:",
+            "    * 0: success",
+            "    * 2: wrong command, options or arguments",
+            "    * 3: invalid input file",
+            "    * 4: no result found",
+            "    * 5: a file could not be read or written",
+            "    * progress messages, errors and warnings are printed to stderr",
             "",
             "For more information, see https://github.com/arnaudporet/pathrider.",
             "",
@@ -85,7 +296,9 @@ func Connect() {
             "Usage: pathrider connect [options] <networkFile> <sourceFile> <targetFile>",
             "",
             "Positional arguments:",
-            "    * <networkFile>: the network encoded in a SIF file",
+            "    * <networkFile>: the network encoded in a SIF, KGML or GraphML file, several",
+            "                     files separated by commas being merged into one",
+            "                     network",
             "    * <sourceFile>: the source nodes listed in a file (one node per line)",
             "    * <targetFile>: the target nodes listed in a file (one node per line)",
             "    * if sources = targets then provide the same node list twice",
@@ -93,11 +306,115 @@ func Connect() {
             "Options:",
             "    * -s/-shortest: also find the shortest connecting paths (default: not used",
             "                    by default)",
+            "    * -m/-max-length <int>: only keep the edges lying on at least one connecting",
+            "                            walk (a path possibly visiting a node twice)",
+            "                            having at most the given number of edges",
+            "                            (default: not used by default)",
+            "    * -k/-kshortest <int>: also find the k shortest connecting paths (simple",
+            "                           paths) for each source/target pair (default: not",
+            "                           used by default)",
+            "    * -l/-list: also list the k shortest connecting paths one by one (requires",
+            "                -k/-kshortest) (default: not used by default)",
+            "    * -via <file>: only keep the connecting paths passing through the waypoints",
+            "                   listed in a file (one node per line) (default: not used",
+            "                   by default)",
+            "    * -via-mode <mode>: go through at least one waypoint (any) or through all",
+            "                        the waypoints in the order of the file (all)",
+            "                        (default: any)",
+            "    * -sign <sign>: only keep the connecting paths whose overall sign, namely the",
+            "                    product of the signs of their edges, is positive",
+            "                    (positive) or negative (negative), or keep them all but",
+            "                    still sign them (any), the paths going through unsigned",
+            "                    edges having no sign (default: not used by default)",
+            "    * -n/-number <int>: the maximal number of simple connecting paths per",
+            "                        source/target pair signed one by one, beyond which",
+            "                        -sign fails (default: 1000)",
+            "    * -signs <file>: a TSV file mapping interaction names (first column) to",
+            "                     signs (second column: +, - or 0), overriding the default",
+            "                     signs (default: activation and expression are positive,",
+            "                     inhibition and repression are negative, the other",
+            "                     interactions are unsigned)",
+            "    * -w/-weighted: the last column of the network file gives the weight of",
+            "                    the interactions (source interaction target weight),",
+            "                    weights being positive numbers, preserved in the output",
+            "                    files, the length of a path (for -s/-shortest and",
+            "                    -k/-kshortest) being the sum of the weights of its",
+            "                    edges (default: not used by default)",
+            "    * -weight-file <file>: read the weights of the interactions from a TSV",
+            "                           file (source interaction target weight, or source",
+            "                           target weight for all the interactions of an",
+            "                           edge) (default: not used by default)",
+            "    * -confidence: the weights are confidence scores between 0 and 1 (e.g.",
+            "                   STRING or OmniPath scores) rather than lengths, the",
+            "                   length of an interaction being -log(score) so that the",
+            "                   shortest paths are the most confident ones (requires",
+            "                   -w/-weighted or -weight-file) (default: not used by",
+            "                   default)",
+            "    * -min-weight <float>: do not consider the interactions weighing less than",
+            "                           the given weight, namely the least confident ones",
+            "                           with -confidence, interactions without weight",
+            "                           weighing 1 (default: not used by default)",
+            "    * -include-interaction <pattern>: only consider the interactions whose name",
+            "                                      matches pattern, namely a literal name, a",
+            "                                      glob (e.g. activation_*), a regular",
+            "                                      expression between slashes (e.g.",
+            "                                      /^(in|ac)/) or @file for the patterns",
+            "                                      listed in file (one pattern per line),",
+            "                                      can be given several times (default: not",
+            "                                      used by default)",
+            "    * -exclude-interaction <pattern>: do not consider the interactions whose",
+            "                                      name matches pattern, as above (default:",
+            "                                      not used by default)",
             "    * -b/-blacklist <file>: a file containing a list of nodes to be blacklisted",
             "                            (one node per line), the paths containing such nodes",
             "                            will not be considered (default: not used by",
             "                            default)",
-            "    * -o/-out <file>: the output SIF file (default: out.sif)",
+            "    * -e/-blacklist-edges <file>: a file containing a list of edges to be",
+            "                                  blacklisted, either as SIF lines (source",
+            "                                  interaction target) or as source/target",
+            "                                  pairs (source target, for all the",
+            "                                  interactions of the edge), the paths",
+            "                                  containing such edges will not be",
+            "                                  considered (default: not used by default)",
+            "    * -p/-provenance: also write, for each output edge and node, the",
+            "                      source/target pairs (source->target) whose connecting",
+            "                      paths it lies on (default: not used by default)",
+            "    * -scc: also write the strongly connected components of the output paths",
+            "            and their condensation (default: not used by default)",
+            "    * -r/-reachability: also write, for each source/target pair, whether the",
+            "                        source node reaches the target node, the length and",
+            "                        the number of the shortest paths, and the number of",
+            "                        intermediate nodes lying on them (default: not used",
+            "                        by default)",
+            "    * -json: also write the reachability report in JSON (requires",
+            "             -r/-reachability) (default: not used by default)",
+            "    * -interaction-attribute <name>: the edge attribute giving the interaction",
+            "                                     names in GraphML network files (default:",
+            "                                     interaction)",
+            "    * -map <file>: a TSV synonym table (one namespace per column, synonyms",
+            "                   separated by \"|\") mapping the identifiers of the",
+            "                   network and of the node files to node names (default:",
+            "                   not used by default)",
+            "    * -map-output <namespace>: the namespace (column) of -map naming the",
+            "                               nodes, in the output files too (default:",
+            "                               the first column)",
+            "    * -c/-comments: skip the lines of the network file starting with \"#\"",
+            "                    (default: not used by default)",
+            "    * -g/-graphviz: also write the output paths in the DOT file format of",
+            "                    Graphviz, highlighting the source and target nodes",
+            "                    (default: not used by default)",
+            "    * -svg: also draw the output paths in a standalone SVG file and in an",
+            "            interactive HTML page, with a layered layout not requiring",
+            "            Graphviz (default: not used by default)",
+            "    * -o/-out <file>: the output SIF file, or GraphML (.graphml),",
+            "                      cytoscape.js (.json) or CX2 (.cx2) file according to",
+            "                      its extension, the last two being annotated for",
+            "                      Cytoscape (node roles, distances, shortest paths)",
+            "                      (default: out.sif)",
+            "    * -q/-quiet: do not print progress messages (default: not used by",
+            "                 default)",
+            "    * -v/-verbose: also print the size of the network and of the results",
+            "                   (default: not used by default)",
             "    * -u/-usage: print usage only",
             "    * -h/-help: print help",
             "",
@@ -106,79 +423,197 @@ func Connect() {
             "               the target nodes in the network",
             "    * out-shortest.sif: a SIF file encoding only the shortest connecting paths",
             "                        (requires -s/-shortest)",
+            "    * out-signs.tsv: a TSV file giving the overall signs of the connecting paths",
+            "                     each edge of out.sif lies on, unknown if they all go",
+            "                     through unsigned edges (requires -sign)",
+            "    * out-k<k>.sif: a SIF file encoding only the k shortest connecting paths",
+            "                    (requires -k/-kshortest)",
+            "    * out-k<k>.tsv: a TSV file listing the k shortest connecting paths, one path",
+            "                    per line, as pathrider paths does (requires -l/-list)",
+            "    * out-provenance.tsv: a TSV file giving, for each edge of out.sif, the",
+            "                          source/target pairs whose connecting paths it lies",
+            "                          on and their number (requires -p/-provenance)",
+            "    * out-provenance-nodes.tsv: the same for the nodes of out.sif (requires",
+            "                                -p/-provenance)",
+            "    * out-components.tsv: a TSV file giving, for each node of out.sif, its",
+            "                          strongly connected component and the size of this",
+            "                          component (requires -scc)",
+            "    * out-condensed.sif: a SIF file encoding the condensation of out.sif,",
+            "                         namely its strongly connected components linked by",
+            "                         their interactions, the cyclic components being",
+            "                         named scc1, scc2, ... (requires -scc)",
+            "    * out-reachability.tsv: a TSV file giving, for each source/target pair,",
+            "                            whether the source node reaches the target node",
+            "                            (reachable), the length (length) and the number",
+            "                            (paths) of the shortest paths, and the number of",
+            "                            intermediate nodes lying on them (intermediates)",
+            "                            (requires -r/-reachability)",
+            "    * out-reachability.json: the same in JSON (requires -json)",
+            "    * out.dot, out-shortest.dot, out-k<k>.dot: the same paths in the DOT file",
+            "                                               format, to be rendered with",
+            "                                               Graphviz (e.g. dot -Tsvg)",
+            "                                               (requires -g/-graphviz)",
+            "    * out.svg, out-shortest.svg, out-k<k>.svg: the same paths drawn with a",
+            "                                               layered layout (requires",
+            "                                               -svg)",
+            "    * out.html, out-shortest.html, out-k<k>.html: the same drawings in",
+            "                                                  interactive HTML pages",
+            "                                                  (requires -svg)",
+            "    * out-mapping.tsv: a TSV file listing the identifiers kept as is because",
+            "                       they are ambiguous or unmapped (requires -map)",
             "",
         },"\n"))
-    } else if filepath.Ext(outFile)!=".sif" {
-        fmt.Println("Error: pathrider connect: "+outFile+": the output SIF file must have the \".sif\" file extension")
+    } else if (outFile!="-") && !IsInList([]string{".sif",".graphml",".json",".cx2"},filepath.Ext(outFile)) {
+        Error(ExitUsage,"pathrider connect: "+outFile+": the output network file must have the \".sif\", \".graphml\", \".json\" or \".cx2\" file extension")
+    } else if !math.IsNaN(k) && ((math.Round(k)!=k) || (k<1)) {
+        Error(ExitUsage,"pathrider connect: k must be a positive integer")
+    } else if !math.IsNaN(maxLength) && ((math.Round(maxLength)!=maxLength) || (maxLength<1)) {
+        Error(ExitUsage,"pathrider connect: max-length must be a positive integer")
+    } else if (math.Round(maxCount)!=maxCount) || (maxCount<1) {
+        Error(ExitUsage,"pathrider connect: number must be a positive integer")
+    } else if (viaMode!="any") && (viaMode!="all") {
+        Error(ExitUsage,"pathrider connect: "+viaMode+": unknown via mode, expecting one of: any, all")
+    } else if (pathSign!="") && (pathSign!="positive") && (pathSign!="negative") && (pathSign!="any") {
+        Error(ExitUsage,"pathrider connect: "+pathSign+": unknown sign, expecting one of: positive, negative, any")
+    } else if confidence && !weighted && (weightFile=="") {
+        Error(ExitUsage,"pathrider connect: -confidence requires weights, expecting -w/-weighted or -weight-file")
     } else if len(flagSet.Args())!=3 {
-        fmt.Println("Error: pathrider connect: wrong number of positional arguments, expecting: <networkFile> <sourceFile> <targetFile>")
+        Error(ExitUsage,"pathrider connect: wrong number of positional arguments, expecting: <networkFile> <sourceFile> <targetFile>")
+    } else if !StdinOnce(append(flagSet.Args(),mapFile,blackFile,blackEdgeFile,weightFile,viaFile,signMapFile)) {
+        Error(ExitUsage,"pathrider connect: the standard input (-) can be read only once")
     } else {
         args=flagSet.Args()
-        fmt.Println("reading network: "+args[0])
-        nodes,edges,edgeNames,err1=ReadNetwork(args[0])
-        if err1!=nil {
-            fmt.Println("Error: pathrider connect: "+args[0]+": "+err1.Error())
-        } else {
-            if blackFile!="" {
-                fmt.Println("reading blacklist: "+blackFile)
-                blackNodes,err1=ReadNodes(blackFile,nodes)
+        net,mapping,blackNeighbours,err1=LoadNetwork("connect",args[0],NetworkOptions{
+            Read:network.ReadOptions{SkipComments:skipComments,Weighted:weighted,InteractionAttribute:interactionAttribute},
+            MapFile:mapFile,
+            MapOutput:mapOutput,
+            WeightFile:weightFile,
+            BlackFile:blackFile,
+            BlackEdgeFile:blackEdgeFile,
+            Confidence:confidence,
+            MinWeight:minWeight,
+            Includes:includes,
+            Excludes:excludes,
+        })
+        if err1==nil {
+            if signMapFile!="" {
+                Progress("reading signs: "+signMapFile)
+                signMap,err1=network.ReadSignMap(signMapFile)
                 if err1!=nil {
-                    fmt.Println("Error: pathrider connect: "+blackFile+": "+err1.Error())
-                } else {
-                    fmt.Println("blacklisting nodes")
-                    nodes,edges,edgeNames,err1=RmNodes(edges,edgeNames,blackNodes)
-                    if err1!=nil {
-                        fmt.Println("Error: pathrider connect: "+blackFile+": "+err1.Error())
-                    }
+                    Error(ErrorCode(err1),"pathrider connect: "+signMapFile+": "+err1.Error())
                 }
             }
             if err1==nil {
-                fmt.Println("reading source nodes: "+args[1])
-                sources,err1=ReadNodes(args[1],nodes)
-                fmt.Println("reading target nodes: "+args[2])
-                targets,err2=ReadNodes(args[2],nodes)
+                Progress("reading source nodes: "+args[1])
+                sources,err1=net.ReadNodes(args[1])
+                Progress("reading target nodes: "+args[2])
+                targets,err2=net.ReadNodes(args[2])
                 if err1!=nil {
-                    fmt.Println("Error: pathrider connect: "+args[1]+": "+err1.Error())
+                    Error(ErrorCode(err1),"pathrider connect: "+args[1]+": "+err1.Error())
                 }
                 if err2!=nil {
-                    fmt.Println("Error: pathrider connect: "+args[2]+": "+err2.Error())
+                    Error(ErrorCode(err2),"pathrider connect: "+args[2]+": "+err2.Error())
                 }
-                if (err1==nil) && (err2==nil) {
-                    fmt.Println("forwarding source nodes")
-                    nodeSucc,edgeSucc=GetSuccessors(edges)
-                    forward=ForwardEdges(sources,nodeSucc,edgeSucc,math.NaN())
-                    fmt.Println("backwarding target nodes")
-                    nodePred,edgePred=GetPredecessors(edges)
-                    backward=BackwardEdges(targets,nodePred,edgePred,math.NaN())
-                    if len(forward)==0 {
-                        fmt.Println("Warning: pathrider connect: "+args[1]+": no forward paths found")
-                    }
-                    if len(backward)==0 {
-                        fmt.Println("Warning: pathrider connect: "+args[2]+": no backward paths found")
+                if (err1==nil) && (err2==nil) && (viaFile!="") {
+                    Progress("reading waypoints: "+viaFile)
+                    waypoints,err1=net.ReadNodes(viaFile)
+                    if err1!=nil {
+                        Error(ErrorCode(err1),"pathrider connect: "+viaFile+": "+err1.Error())
                     }
-                    if (len(forward)!=0) && (len(backward)!=0) {
-                        fmt.Println("computing connecting paths")
-                        intersect=IntersectEdges(forward,backward)
-                        if len(intersect)==0 {
-                            fmt.Println("Warning: pathrider connect: no connecting paths found")
-                        } else {
-                            fmt.Println("writing connecting paths: "+outFile)
-                            err1=WriteNetwork(outFile,intersect,edgeNames)
+                }
+                if (err1==nil) && (err2==nil) && (mapping!=nil) {
+                    err1=ReportMapping("connect",mapping,OutFile(outFile,"-mapping",".tsv"))
+                }
+                if (err1==nil) && (err2==nil) {
+                    intersect=ConnectNodes("connect",net,sources,targets,args[1],args[2])
+                    if intersect.NumEdges()!=0 {
+                        if viaFile!="" {
+                            Progress("computing connecting paths through waypoints")
+                            intersect=intersect.Via(sources,targets,waypoints,viaMode=="all")
+                        }
+                        if (intersect.NumEdges()!=0) && !math.IsNaN(maxLength) {
+                            Progress("limiting connecting paths to "+strconv.Itoa(int(maxLength))+" edges")
+                            intersect=intersect.Within(sources,targets,maxLength)
+                        }
+                        if (intersect.NumEdges()!=0) && (pathSign!="") {
+                            Progress("signing connecting paths")
+                            positive,err1=intersect.SignedConnect(sources,targets,waypoints,viaMode=="all",signMap,1,maxLength,maxCount)
+                            if err1==nil {
+                                negative,err1=intersect.SignedConnect(sources,targets,waypoints,viaMode=="all",signMap,-1,maxLength,maxCount)
+                            }
                             if err1!=nil {
-                                fmt.Println("Error: pathrider connect: "+outFile+": "+err1.Error())
-                            } else if getShortest {
-                                fmt.Println("computing shortest connecting paths")
-                                noSelfLoop,selfLooped=RmSelfLoops(intersect)
-                                nodeSucc,edgeSucc=GetSuccessors(noSelfLoop)
-                                allShortest=AllShortestPaths(sources,targets,selfLooped,nodeSucc,edgeSucc)
-                                outFilePath,outFileBase=filepath.Split(outFile)
-                                outFileBase=strings.TrimSuffix(outFileBase,".sif")
-                                outFileBase+="-shortest.sif"
-                                outFile=filepath.Join(outFilePath,outFileBase)
-                                fmt.Println("writing shortest connecting paths: "+outFile)
-                                err1=WriteNetwork(outFile,allShortest,edgeNames)
+                                Error(ExitUsage,"pathrider connect: "+err1.Error()+" (more than "+strconv.Itoa(int(maxCount))+" simple paths for a source/target pair), see -n/-number and -m/-max-length")
+                            } else if pathSign=="positive" {
+                                intersect=positive
+                            } else if pathSign=="negative" {
+                                intersect=negative
+                            }
+                        }
+                        if (err1==nil) && (intersect.NumEdges()==0) {
+                            if (pathSign=="positive") || (pathSign=="negative") {
+                                Empty("pathrider connect: no "+pathSign+" connecting paths found")
+                            } else if viaFile!="" {
+                                Empty("pathrider connect: "+viaFile+": no connecting paths found through the waypoints")
+                            } else {
+                                Empty("pathrider connect: no connecting paths found within the maximal length")
+                            }
+                        } else if err1==nil {
+                            highlight=network.Highlight{Sources:sources,Targets:targets,BlackNeighbours:blackNeighbours,Signs:signMap}
+                            err1=WriteResult("connect","connecting paths",intersect,outFile,writeDOT,writeSVG,highlight)
+                            if (err1==nil) && getReachability {
+                                Progress("computing reachability")
+                                reaches=intersect.Reachability(sources,targets)
+                                reachFile=OutFile(outFile,"-reachability",".tsv")
+                                Progress("writing reachability: "+reachFile)
+                                err1=network.WriteReachability(reachFile,reaches)
+                                if (err1==nil) && writeJSON {
+                                    reachFile=OutFile(outFile,"-reachability",".json")
+                                    Progress("writing reachability: "+reachFile)
+                                    err1=network.WriteReachabilityJSON(reachFile,reaches)
+                                }
+                                if err1!=nil {
+                                    Error(ExitIO,"pathrider connect: "+reachFile+": "+err1.Error())
+                                }
+                            }
+                            if (err1==nil) && getProvenance {
+                                Progress("computing provenance")
+                                err1=WriteProvenance(intersect,PairProvenance(intersect,sources,targets),"pairs",OutFile(outFile,"-provenance",".tsv"),OutFile(outFile,"-provenance-nodes",".tsv"))
+                                if err1!=nil {
+                                    Error(ExitIO,"pathrider connect: "+err1.Error())
+                                }
+                            }
+                            if (err1==nil) && getComponents {
+                                Progress("computing strongly connected components")
+                                err1=WriteComponents("connect",intersect,OutFile(outFile,"-components",".tsv"),NetworkFile(outFile,"-condensed"))
+                                if err1!=nil {
+                                    Error(ExitIO,"pathrider connect: "+err1.Error())
+                                }
+                            }
+                            if (err1==nil) && (pathSign!="") {
+                                signFile=OutFile(outFile,"-signs",".tsv")
+                                Progress("writing connecting path signs: "+signFile)
+                                err1=intersect.WriteEdgeTable(signFile,[]string{"signs"},EdgeSigns(intersect,positive,negative))
                                 if err1!=nil {
-                                    fmt.Println("Error: pathrider connect: "+outFile+": "+err1.Error())
+                                    Error(ExitIO,"pathrider connect: "+signFile+": "+err1.Error())
+                                }
+                            }
+                            if (err1==nil) && getShortest {
+                                Progress("computing shortest connecting paths")
+                                allShortest=intersect.Shortest(sources,targets)
+                                err1=WriteResult("connect","shortest connecting paths",allShortest,NetworkFile(outFile,"-shortest"),writeDOT,writeSVG,highlight)
+                            }
+                            if (err1==nil) && !math.IsNaN(k) {
+                                Progress("computing "+strconv.Itoa(int(k))+" shortest connecting paths")
+                                kPaths=intersect.KShortestPaths(sources,targets,int(k))
+                                kShortest=intersect.Subnetwork(kPaths)
+                                err1=WriteResult("connect",strconv.Itoa(int(k))+" shortest connecting paths",kShortest,NetworkFile(outFile,"-k"+strconv.Itoa(int(k))),writeDOT,writeSVG,highlight)
+                                if (err1==nil) && listPaths {
+                                    pathFile=OutFile(outFile,"-k"+strconv.Itoa(int(k)),".tsv")
+                                    Progress("writing "+strconv.Itoa(int(k))+" shortest connecting paths: "+pathFile)
+                                    err1=network.WritePaths(pathFile,kPaths)
+                                    if err1!=nil {
+                                        Error(ExitIO,"pathrider connect: "+pathFile+": "+err1.Error())
+                                    }
                                 }
                             }
                         }
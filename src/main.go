@@ -25,23 +25,28 @@ func main() {
     flagSet.BoolVar(&license,"l",false,"")
     err=flagSet.Parse(os.Args[1:])
     if err!=nil {
-        fmt.Println("Error: pathrider: "+err.Error())
+        Error(ExitUsage,"pathrider: "+err.Error())
     } else if help {
         fmt.Println(strings.Join([]string{
             "",
             "pathrider is a tool for finding paths of interest in networks.",
             "",
-            "pathrider currently provides 2 commands:",
+            "pathrider currently provides 6 commands:",
             "    * connect: find the paths connecting some nodes of interest in a network",
             "    * stream: find the upstream/downstream paths starting from some nodes of",
             "              interest in a network",
+            "    * paths: list the individual paths connecting some nodes of interest in a",
+            "             network",
+            "    * cycles: list the feedback loops (elementary cycles) of a network",
+            "    * scc: find the strongly connected components (cyclic cores) of a network",
+            "    * stats: summarise a network",
             "",
             "Usage:",
             "    * pathrider [options]",
             "    * pathrider <command> [options] <arguments>",
             "",
             "Positional argument:",
-            "    * <command>: connect, stream",
+            "    * <command>: connect, stream, paths, cycles, scc, stats",
             "",
             "Options:",
             "    * -l/-license: print the GNU General Public License under which pathrider is",
@@ -61,7 +66,7 @@ func main() {
             "    * pathrider <command> [options] <arguments>",
             "",
             "Positional argument:",
-            "    * <command>: connect, stream",
+            "    * <command>: connect, stream, paths, cycles, scc, stats",
             "",
             "Options:",
             "    * -l/-license: print the GNU General Public License under which pathrider is",
@@ -89,15 +94,24 @@ func main() {
             "",
         },"\n"))
     } else if len(flagSet.Args())==0 {
-        fmt.Println("Error: pathrider: missing command, expecting one of: connect, stream")
+        Error(ExitUsage,"pathrider: missing command, expecting one of: connect, stream, paths, cycles, scc, stats")
     } else {
         command=flagSet.Arg(0)
         if command=="connect" {
             Connect()
         } else if command=="stream" {
             Stream()
+        } else if command=="paths" {
+            Paths()
+        } else if command=="cycles" {
+            Cycles()
+        } else if command=="scc" {
+            SCC()
+        } else if command=="stats" {
+            Stats()
         } else {
-            fmt.Println("Error: pathrider: "+command+": unknown command, expecting one of: connect, stream")
+            Error(ExitUsage,"pathrider: "+command+": unknown command, expecting one of: connect, stream, paths, cycles, scc, stats")
         }
     }
+    os.Exit(exitCode)
 }
@@ -0,0 +1,367 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package main
+import (
+    "bytes"
+    "errors"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+// testSIF is a small network with a branching (B), an inhibition (C -| A) and
+// a root (X).
+const testSIF="A\tactivation\tB\n"+
+    "B\tactivation\tC\n"+
+    "C\tinhibition\tA\n"+
+    "B\tactivation\tD\n"+
+    "D\tactivation\tE\n"+
+    "C\tactivation\tE\n"+
+    "E\tactivation\tF\n"+
+    "X\tactivation\tA\n"
+// TestMain runs pathrider itself, instead of the tests, when the test binary
+// is re-executed by runPathrider.
+func TestMain(m *testing.M) {
+    if os.Getenv("PATHRIDER_TEST_MAIN")=="1" {
+        main()
+    }
+    os.Exit(m.Run())
+}
+func TestExitCodes(t *testing.T) {
+    var (
+        i,code int
+        dir,stderr string
+        tests []struct {
+            args []string
+            code int
+            stderr string
+        }
+    )
+    dir=testDir(t,map[string]string{
+        "network.sif":testSIF,
+        "malformed.sif":"A\tactivation\n",
+        "cycle.sif":"A\tactivation\tB\nB\tactivation\tA\n",
+        "A.txt":"A\n",
+        "F.txt":"F\n",
+        "Z.txt":"Z\n",
+    })
+    tests=[]struct {
+        args []string
+        code int
+        stderr string
+    }{
+        {[]string{"-help"},0,""},
+        {[]string{},ExitUsage,"missing command"},
+        {[]string{"unknown"},ExitUsage,"unknown: unknown command"},
+        {[]string{"connect","-unknown"},ExitUsage,"flag provided but not defined"},
+        {[]string{"connect","network.sif","A.txt"},ExitUsage,"wrong number of positional arguments"},
+        {[]string{"connect","-o","out.txt","network.sif","A.txt","F.txt"},ExitUsage,"must have the \".sif\""},
+        {[]string{"connect","missing.sif","A.txt","F.txt"},ExitIO,"missing.sif"},
+        {[]string{"connect","malformed.sif","A.txt","F.txt"},ExitInput,"malformed.sif"},
+        {[]string{"connect","network.sif","Z.txt","F.txt"},ExitInput,"Z.txt"},
+        {[]string{"connect","network.sif","F.txt","A.txt"},ExitEmpty,"Warning: "},
+        {[]string{"connect","-o","out.sif","network.sif","A.txt","F.txt"},0,""},
+        // the negative walks around the C -| A feedback loop are not paths
+        {[]string{"connect","-sign","negative","-o","negative.sif","network.sif","A.txt","F.txt"},ExitEmpty,"no negative connecting paths found"},
+        {[]string{"connect","-sign","positive","-o","positive.sif","network.sif","A.txt","F.txt"},0,""},
+        {[]string{"connect","-sign","positive","-n","1","-o","positive.sif","network.sif","A.txt","F.txt"},ExitUsage,"too many connecting paths to sign"},
+        {[]string{"connect","-n","0","network.sif","A.txt","F.txt"},ExitUsage,"number must be a positive integer"},
+        {[]string{"connect","-confidence","network.sif","A.txt","F.txt"},ExitUsage,"-confidence requires weights"},
+        {[]string{"stream","-confidence","network.sif","A.txt","down"},ExitUsage,"-confidence requires weights"},
+        {[]string{"stream","network.sif","A.txt","sideways"},ExitUsage,"sideways: unknown direction"},
+        {[]string{"stream","-o","out.sif","network.sif","A.txt","down"},0,""},
+        {[]string{"stream","network.sif","X.txt","up"},ExitIO,"X.txt"},
+        // the paths are written despite the lack of terminal nodes
+        {[]string{"stream","-t","-o","cycle-out.sif","cycle.sif","A.txt","down"},0,"Warning: pathrider stream: A.txt: no downstream terminal nodes found"},
+        {[]string{"paths","-o","out.tsv","network.sif","A.txt","F.txt"},0,""},
+        {[]string{"paths","network.sif","F.txt","A.txt"},ExitEmpty,"Warning: "},
+    }
+    for i=range tests {
+        code,_,stderr=runPathrider(t,dir,tests[i].args...)
+        if code!=tests[i].code {
+            t.Errorf("pathrider %s: got exit code %v, want %v",strings.Join(tests[i].args," "),code,tests[i].code)
+        }
+        if (tests[i].stderr=="") && (strings.Contains(stderr,"Error: ") || strings.Contains(stderr,"Warning: ")) {
+            t.Errorf("pathrider %s: got %q on stderr, want no errors or warnings",strings.Join(tests[i].args," "),stderr)
+        } else if !strings.Contains(stderr,tests[i].stderr) {
+            t.Errorf("pathrider %s: got %q on stderr, want %q",strings.Join(tests[i].args," "),stderr,tests[i].stderr)
+        }
+    }
+}
+func TestResults(t *testing.T) {
+    var (
+        err error
+        dir,file string
+    )
+    dir=testDir(t,map[string]string{"network.sif":testSIF,"A.txt":"A\n","F.txt":"F\n"})
+    runPathrider(t,dir,"connect","-o","out.sif","network.sif","A.txt","F.txt")
+    checkFile(t,filepath.Join(dir,"out.sif"),"A\tactivation\tB\nB\tactivation\tC\nB\tactivation\tD\nC\tinhibition\tA\nC\tactivation\tE\nD\tactivation\tE\nE\tactivation\tF\n")
+    runPathrider(t,dir,"paths","-o","out.tsv","network.sif","A.txt","F.txt")
+    checkFile(t,filepath.Join(dir,"out.tsv"),"source\ttarget\tlength\tnodes\tinteractions\n"+
+        "A\tF\t4\tA;B;C;E;F\tactivation;activation;activation;activation\n"+
+        "A\tF\t4\tA;B;D;E;F\tactivation;activation;activation;activation\n",
+    )
+    runPathrider(t,dir,"connect","-svg","-o","drawn.sif","network.sif","A.txt","F.txt")
+    for _,file=range []string{"drawn.svg","drawn.html"} {
+        _,err=os.Stat(filepath.Join(dir,file))
+        if err!=nil {
+            t.Errorf("pathrider connect -svg: %v",err)
+        }
+    }
+    runPathrider(t,dir,"connect","-o","empty.sif","network.sif","F.txt","A.txt")
+    _,err=os.Stat(filepath.Join(dir,"empty.sif"))
+    if err==nil {
+        t.Errorf("pathrider connect: empty.sif written without connecting paths")
+    }
+}
+func TestProvenance(t *testing.T) {
+    var (
+        dir string
+    )
+    dir=testDir(t,map[string]string{"network.sif":testSIF,"sources.txt":"A\nX\n","F.txt":"F\n","seeds.txt":"B\nD\n"})
+    runPathrider(t,dir,"connect","-p","network.sif","sources.txt","F.txt")
+    checkFile(t,filepath.Join(dir,"out-provenance.tsv"),
+        "source\tinteraction\ttarget\tpairs\tcount\n"+
+        "A\tactivation\tB\tA->F,X->F\t2\n"+
+        "X\tactivation\tA\tX->F\t1\n"+
+        "B\tactivation\tC\tA->F,X->F\t2\n"+
+        "B\tactivation\tD\tA->F,X->F\t2\n"+
+        "C\tinhibition\tA\tA->F,X->F\t2\n"+
+        "C\tactivation\tE\tA->F,X->F\t2\n"+
+        "D\tactivation\tE\tA->F,X->F\t2\n"+
+        "E\tactivation\tF\tA->F,X->F\t2\n",
+    )
+    checkFile(t,filepath.Join(dir,"out-provenance-nodes.tsv"),
+        "node\tpairs\tcount\n"+
+        "A\tA->F,X->F\t2\n"+
+        "B\tA->F,X->F\t2\n"+
+        "X\tX->F\t1\n"+
+        "C\tA->F,X->F\t2\n"+
+        "D\tA->F,X->F\t2\n"+
+        "E\tA->F,X->F\t2\n"+
+        "F\tA->F,X->F\t2\n",
+    )
+    runPathrider(t,dir,"stream","-p","-o","stream.sif","network.sif","seeds.txt","down")
+    checkFile(t,filepath.Join(dir,"stream-provenance.tsv"),
+        "source\tinteraction\ttarget\tseeds\tcount\n"+
+        "B\tactivation\tC\tB\t1\n"+
+        "B\tactivation\tD\tB\t1\n"+
+        "D\tactivation\tE\tB,D\t2\n"+
+        "C\tinhibition\tA\tB\t1\n"+
+        "C\tactivation\tE\tB\t1\n"+
+        "E\tactivation\tF\tB,D\t2\n"+
+        "A\tactivation\tB\tB\t1\n",
+    )
+    checkFile(t,filepath.Join(dir,"stream-provenance-nodes.tsv"),
+        "node\tseeds\tcount\n"+
+        "B\tB\t1\n"+
+        "C\tB\t1\n"+
+        "D\tB,D\t2\n"+
+        "E\tB,D\t2\n"+
+        "A\tB\t1\n"+
+        "F\tB,D\t2\n",
+    )
+}
+func TestStreamBoth(t *testing.T) {
+    var (
+        err error
+        code int
+        dir,stderr string
+    )
+    dir=testDir(t,map[string]string{"network.sif":testSIF,"A.txt":"A\n"})
+    runPathrider(t,dir,"stream","-t","network.sif","A.txt","both")
+    checkFile(t,filepath.Join(dir,"out.sif"),"C\tinhibition\tA\nX\tactivation\tA\nB\tactivation\tC\nA\tactivation\tB\nB\tactivation\tD\nC\tactivation\tE\nD\tactivation\tE\nE\tactivation\tF\n")
+    // the edges of the cycle are both upstream and downstream of A
+    checkFile(t,filepath.Join(dir,"out-direction.tsv"),
+        "source\tinteraction\ttarget\tdirection\n"+
+        "C\tinhibition\tA\tboth\n"+
+        "X\tactivation\tA\tup\n"+
+        "B\tactivation\tC\tboth\n"+
+        "A\tactivation\tB\tboth\n"+
+        "B\tactivation\tD\tdown\n"+
+        "C\tactivation\tE\tdown\n"+
+        "D\tactivation\tE\tdown\n"+
+        "E\tactivation\tF\tdown\n",
+    )
+    checkFile(t,filepath.Join(dir,"out-terminal-up.txt"),"X\n")
+    checkFile(t,filepath.Join(dir,"out-terminal-down.txt"),"F\n")
+    // X has no upstream paths, which is only warned about
+    dir=testDir(t,map[string]string{"network.sif":testSIF,"X.txt":"X\n"})
+    code,_,stderr=runPathrider(t,dir,"stream","-t","network.sif","X.txt","both")
+    if code!=0 {
+        t.Errorf("pathrider stream both: got exit code %v, want 0",code)
+    }
+    if !strings.Contains(stderr,"Warning: pathrider stream: X.txt: no upstream paths found") {
+        t.Errorf("pathrider stream both: no warning in %q",stderr)
+    }
+    _,err=os.Stat(filepath.Join(dir,"out-terminal-up.txt"))
+    if err==nil {
+        t.Errorf("pathrider stream both: out-terminal-up.txt written without upstream paths")
+    }
+    checkFile(t,filepath.Join(dir,"out-terminal-down.txt"),"F\n")
+}
+func TestSCC(t *testing.T) {
+    var (
+        code int
+        dir string
+    )
+    dir=testDir(t,map[string]string{"network.sif":testSIF,"cycle.sif":"A\tactivation\tB\nB\tactivation\tA\n"})
+    code,_,_=runPathrider(t,dir,"scc","network.sif")
+    if code!=0 {
+        t.Errorf("pathrider scc: got exit code %v, want 0",code)
+    }
+    checkFile(t,filepath.Join(dir,"out.sif"),"scc1\tactivation\tD\nD\tactivation\tE\nscc1\tactivation\tE\nE\tactivation\tF\nX\tactivation\tscc1\n")
+    checkFile(t,filepath.Join(dir,"out-components.tsv"),"node\tcomponent\tsize\nA\tscc1\t3\nB\tscc1\t3\nC\tscc1\t3\nD\tD\t1\nE\tE\t1\nF\tF\t1\nX\tX\t1\n")
+    // a single component has no condensation, but the components are written
+    code,_,_=runPathrider(t,dir,"scc","-o","cycle-out.sif","cycle.sif")
+    if code!=0 {
+        t.Errorf("pathrider scc: got exit code %v, want 0",code)
+    }
+    checkFile(t,filepath.Join(dir,"cycle-out-components.tsv"),"node\tcomponent\tsize\nA\tscc1\t2\nB\tscc1\t2\n")
+}
+func TestMapping(t *testing.T) {
+    var (
+        code int
+        dir string
+    )
+    dir=testDir(t,map[string]string{"network.sif":testSIF,"mapping.tsv":"symbol\tentrez\nA\t101\nB\t102\nC\t103\nD\t104\nE\t105\nF\t106\nX\t100|999\nY\t999\n","sources.txt":"101\n","targets.txt":"F\n"})
+    code,_,_=runPathrider(t,dir,"connect","-map","mapping.tsv","-map-output","entrez","network.sif","sources.txt","targets.txt")
+    if code!=0 {
+        t.Errorf("pathrider connect -map: got exit code %v, want 0",code)
+    }
+    checkFile(t,filepath.Join(dir,"out.sif"),"101\tactivation\t102\n102\tactivation\t103\n102\tactivation\t104\n103\tinhibition\t101\n103\tactivation\t105\n104\tactivation\t105\n105\tactivation\t106\n")
+    checkFile(t,filepath.Join(dir,"out-mapping.tsv"),"identifier\tstatus\tnames\n")
+}
+func TestCyclesLimit(t *testing.T) {
+    var (
+        code int
+        dir,stderr string
+    )
+    dir=testDir(t,map[string]string{"network.sif":"A\tactivation\tB\nB\tactivation\tA\nB\tactivation\tC\nC\tinhibition\tB\nD\tactivation\tD\n"})
+    code,_,stderr=runPathrider(t,dir,"cycles","-n","2","network.sif")
+    if code!=0 {
+        t.Errorf("pathrider cycles -n 2: got exit code %v, want 0",code)
+    }
+    if !strings.Contains(stderr,"Warning: pathrider cycles: the maximal number of cycles is reached") {
+        t.Errorf("pathrider cycles -n 2: no warning in %q",stderr)
+    }
+    checkFile(t,filepath.Join(dir,"out.tsv"),"length\tnodes\tinteractions\tsign\n1\tD;D\tactivation\tpositive\n2\tA;B;A\tactivation;activation\tpositive\n")
+}
+func TestQuietVerbose(t *testing.T) {
+    var (
+        dir,stderr,quietStderr,verboseStderr string
+    )
+    dir=testDir(t,map[string]string{"network.sif":testSIF,"A.txt":"A\n","F.txt":"F\n"})
+    _,_,stderr=runPathrider(t,dir,"connect","network.sif","A.txt","F.txt")
+    _,_,quietStderr=runPathrider(t,dir,"connect","-q","network.sif","A.txt","F.txt")
+    _,_,verboseStderr=runPathrider(t,dir,"connect","-v","network.sif","A.txt","F.txt")
+    if stderr=="" {
+        t.Errorf("pathrider connect: no progress messages")
+    }
+    if quietStderr!="" {
+        t.Errorf("pathrider connect -q: got %q on stderr, want nothing",quietStderr)
+    }
+    if !(len(verboseStderr)>len(stderr)) || !strings.HasPrefix(verboseStderr,strings.SplitN(stderr,"\n",2)[0]) {
+        t.Errorf("pathrider connect -v: got %q on stderr, want more than %q",verboseStderr,stderr)
+    }
+}
+func TestStdinStdout(t *testing.T) {
+    var (
+        err error
+        code int
+        dir,stdout,stderr string
+    )
+    dir=testDir(t,map[string]string{"network.sif":testSIF,"A.txt":"A\n","F.txt":"F\n"})
+    // the network from stdin, the result to stdout
+    code,stdout,_=runPathriderStdin(t,dir,testSIF,"connect","-q","-o","-","-","A.txt","F.txt")
+    if code!=0 {
+        t.Errorf("pathrider connect -o - -: got exit code %v, want 0",code)
+    }
+    if stdout!="A\tactivation\tB\nB\tactivation\tC\nB\tactivation\tD\nC\tinhibition\tA\nC\tactivation\tE\nD\tactivation\tE\nE\tactivation\tF\n" {
+        t.Errorf("pathrider connect -o - -: got %q on stdout",stdout)
+    }
+    // the source nodes from stdin
+    code,stdout,_=runPathriderStdin(t,dir,"A\n","stream","-q","-o","-","network.sif","-","up")
+    if (code!=0) || (stdout!="C\tinhibition\tA\nX\tactivation\tA\nB\tactivation\tC\nA\tactivation\tB\n") {
+        t.Errorf("pathrider stream -o - network.sif -: got exit code %v and %q on stdout",code,stdout)
+    }
+    code,_,stderr=runPathriderStdin(t,dir,testSIF,"connect","-","-","F.txt")
+    if (code!=ExitUsage) || !strings.Contains(stderr,"the standard input (-) can be read only once") {
+        t.Errorf("pathrider connect - -: got exit code %v and %q on stderr, want %v",code,stderr,ExitUsage)
+    }
+    // - among the comma-separated network files counts as well
+    code,_,stderr=runPathriderStdin(t,dir,testSIF,"connect","network.sif,-","-","F.txt")
+    if (code!=ExitUsage) || !strings.Contains(stderr,"the standard input (-) can be read only once") {
+        t.Errorf("pathrider connect network.sif,- -: got exit code %v and %q on stderr, want %v",code,stderr,ExitUsage)
+    }
+    // the additional outputs go to the current directory, with a warning
+    code,_,stderr=runPathrider(t,dir,"connect","-q","-o","-","-p","network.sif","A.txt","F.txt")
+    if (code!=0) || !strings.Contains(stderr,"Warning: writing out-provenance.tsv in the current directory") {
+        t.Errorf("pathrider connect -o - -p: got exit code %v and %q on stderr",code,stderr)
+    }
+    _,err=os.Stat(filepath.Join(dir,"out-provenance.tsv"))
+    if err!=nil {
+        t.Errorf("pathrider connect -o - -p: %v",err)
+    }
+}
+// runPathrider runs pathrider in dir with the given arguments, returning its
+// exit code, stdout and stderr.
+func runPathrider(t *testing.T,dir string,args ...string) (int,string,string) {
+    t.Helper()
+    return runPathriderStdin(t,dir,"",args...)
+}
+// runPathriderStdin is runPathrider with stdin as the standard input.
+func runPathriderStdin(t *testing.T,dir,stdin string,args ...string) (int,string,string) {
+    var (
+        err error
+        code int
+        stdout,stderr bytes.Buffer
+        exitErr *exec.ExitError
+        cmd *exec.Cmd
+    )
+    t.Helper()
+    cmd=exec.Command(os.Args[0],args...)
+    cmd.Dir=dir
+    cmd.Env=append(os.Environ(),"PATHRIDER_TEST_MAIN=1")
+    cmd.Stdin=strings.NewReader(stdin)
+    cmd.Stdout=&stdout
+    cmd.Stderr=&stderr
+    err=cmd.Run()
+    if errors.As(err,&exitErr) {
+        code=exitErr.ExitCode()
+    } else if err!=nil {
+        t.Fatal(err)
+    }
+    return code,stdout.String(),stderr.String()
+}
+// testDir writes the given files to a temporary directory and returns it.
+func testDir(t *testing.T,files map[string]string) string {
+    var (
+        err error
+        dir,name string
+    )
+    t.Helper()
+    dir=t.TempDir()
+    for name=range files {
+        err=os.WriteFile(filepath.Join(dir,name),[]byte(files[name]),0644)
+        if err!=nil {
+            t.Fatal(err)
+        }
+    }
+    return dir
+}
+func checkFile(t *testing.T,fileName,want string) {
+    var (
+        err error
+        content []byte
+    )
+    t.Helper()
+    content,err=os.ReadFile(fileName)
+    if err!=nil {
+        t.Errorf("%s: %v",filepath.Base(fileName),err)
+    } else if string(content)!=want {
+        t.Errorf("%s: got\n%s\nwant\n%s",filepath.Base(fileName),content,want)
+    }
+}
@@ -0,0 +1,291 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package main
+import (
+    "flag"
+    "fmt"
+    "github.com/arnaudporet/pathrider/network"
+    "math"
+    "os"
+    "path/filepath"
+    "strings"
+)
+func SCC() {
+    var (
+        err1 error
+        help,usage,weighted,confidence,skipComments bool
+        minWeight float64
+        outFile,interactionAttribute,mapFile,mapOutput,blackFile,blackEdgeFile,weightFile string
+        args []string
+        net *network.Network
+        mapping *network.Mapping
+        includes,excludes StringList
+        flagSet *flag.FlagSet
+    )
+    flagSet=flag.NewFlagSet("",flag.ContinueOnError)
+    flagSet.Usage=func() {}
+    flagSet.BoolVar(&help,"help",false,"")
+    flagSet.BoolVar(&help,"h",false,"")
+    flagSet.BoolVar(&usage,"usage",false,"")
+    flagSet.BoolVar(&usage,"u",false,"")
+    flagSet.BoolVar(&quiet,"quiet",false,"")
+    flagSet.BoolVar(&quiet,"q",false,"")
+    flagSet.BoolVar(&verbose,"verbose",false,"")
+    flagSet.BoolVar(&verbose,"v",false,"")
+    flagSet.StringVar(&outFile,"out","out.sif","")
+    flagSet.StringVar(&outFile,"o","out.sif","")
+    flagSet.StringVar(&blackFile,"blacklist","","")
+    flagSet.StringVar(&blackFile,"b","","")
+    flagSet.StringVar(&blackEdgeFile,"blacklist-edges","","")
+    flagSet.StringVar(&blackEdgeFile,"e","","")
+    flagSet.BoolVar(&weighted,"weighted",false,"")
+    flagSet.BoolVar(&weighted,"w",false,"")
+    flagSet.StringVar(&weightFile,"weight-file","","")
+    flagSet.BoolVar(&confidence,"confidence",false,"")
+    flagSet.Float64Var(&minWeight,"min-weight",math.NaN(),"")
+    flagSet.Var(&includes,"include-interaction","")
+    flagSet.Var(&excludes,"exclude-interaction","")
+    flagSet.StringVar(&interactionAttribute,"interaction-attribute","","")
+    flagSet.StringVar(&mapFile,"map","","")
+    flagSet.StringVar(&mapOutput,"map-output","","")
+    flagSet.BoolVar(&skipComments,"comments",false,"")
+    flagSet.BoolVar(&skipComments,"c",false,"")
+    err1=flagSet.Parse(os.Args[2:])
+    if err1!=nil {
+        Error(ExitUsage,"pathrider scc: "+err1.Error())
+    } else if help {
+        fmt.Println(strings.Join([]string{
+            "",
+            "Find the strongly connected components of a network, namely its cyclic cores.",
+            "",
+            "Typical use is to tell which parts of a network (e.g. the paths found by",
+            "pathrider connect) lie on cycles, and to collapse these parts into single",
+            "nodes in order to get the acyclic skeleton of the network (its",
+            "condensation).",
+            "",
+            "Usage: pathrider scc [options] <networkFile>",
+            "",
+            "Positional argument:",
+            "    * <networkFile>: the network encoded in a SIF, KGML or GraphML file, several",
+            "                     files separated by commas being merged into one",
+            "                     network",
+            "",
+            "Options:",
+            "    * -w/-weighted: the last column of the network file gives the weight of",
+            "                    the interactions (source interaction target weight),",
+            "                    weights being positive numbers, the condensation being",
+            "                    unweighted (default: not used by default)",
+            "    * -weight-file <file>: read the weights of the interactions from a TSV",
+            "                           file (source interaction target weight, or source",
+            "                           target weight for all the interactions of an",
+            "                           edge) (default: not used by default)",
+            "    * -confidence: the weights are confidence scores between 0 and 1 (e.g.",
+            "                   STRING or OmniPath scores) rather than lengths, the",
+            "                   length of an interaction being -log(score) so that the",
+            "                   shortest paths are the most confident ones (requires",
+            "                   -w/-weighted or -weight-file) (default: not used by",
+            "                   default)",
+            "    * -min-weight <float>: do not consider the interactions weighing less than",
+            "                           the given weight, namely the least confident ones",
+            "                           with -confidence, interactions without weight",
+            "                           weighing 1 (default: not used by default)",
+            "    * -include-interaction <pattern>: only consider the interactions whose name",
+            "                                      matches pattern, namely a literal name, a",
+            "                                      glob (e.g. activation_*), a regular",
+            "                                      expression between slashes (e.g.",
+            "                                      /^(in|ac)/) or @file for the patterns",
+            "                                      listed in file (one pattern per line),",
+            "                                      can be given several times (default: not",
+            "                                      used by default)",
+            "    * -exclude-interaction <pattern>: do not consider the interactions whose",
+            "                                      name matches pattern, as above (default:",
+            "                                      not used by default)",
+            "    * -b/-blacklist <file>: a file containing a list of nodes to be blacklisted",
+            "                            (one node per line), the paths containing such nodes",
+            "                            will not be considered (default: not used by",
+            "                            default)",
+            "    * -e/-blacklist-edges <file>: a file containing a list of edges to be",
+            "                                  blacklisted, either as SIF lines (source",
+            "                                  interaction target) or as source/target",
+            "                                  pairs (source target, for all the",
+            "                                  interactions of the edge), the paths",
+            "                                  containing such edges will not be",
+            "                                  considered (default: not used by default)",
+            "    * -interaction-attribute <name>: the edge attribute giving the interaction",
+            "                                     names in GraphML network files (default:",
+            "                                     interaction)",
+            "    * -map <file>: a TSV synonym table (one namespace per column, synonyms",
+            "                   separated by \"|\") mapping the identifiers of the",
+            "                   network and of the node files to node names (default:",
+            "                   not used by default)",
+            "    * -map-output <namespace>: the namespace (column) of -map naming the",
+            "                               nodes, in the output files too (default:",
+            "                               the first column)",
+            "    * -c/-comments: skip the lines of the network file starting with \"#\"",
+            "                    (default: not used by default)",
+            "    * -o/-out <file>: the output SIF file, or GraphML file if ending with",
+            "                      .graphml (default: out.sif)",
+            "    * -q/-quiet: do not print progress messages (default: not used by",
+            "                 default)",
+            "    * -v/-verbose: also print the size of the network and of the results",
+            "                   (default: not used by default)",
+            "    * -u/-usage: print usage only",
+            "    * -h/-help: print help",
+            "",
+            "Output files (unless changed with -o/-out):",
+            "    * out.sif: a SIF file encoding the condensation of the network, namely its",
+            "               strongly connected components linked by their interactions,",
+            "               the cyclic components being named scc1, scc2, ... and the",
+            "               acyclic ones after their node",
+            "    * out-components.tsv: a TSV file giving, for each node of the network, its",
+            "                          strongly connected component and the size of this",
+            "                          component",
+            "    * out-mapping.tsv: a TSV file listing the identifiers kept as is because",
+            "                       they are ambiguous or unmapped (requires -map)",
+            "",
+            "Cautions:",
+            "    * the network must be in the SIF, KGML or GraphML file format (see the",
+            "      readme file of pathrider)",
+            "    * lines listing several targets (source interaction target1 target2 ...)",
+            "      are expanded into one edge per target",
+            "    * lines made of a single node add this node without any edge",
+            "    * edge duplicates are automatically removed",
+            "    * edges are assumed to be directed",
+            "    * - stands for the standard input in place of an input file (once at",
+            "      most) and for the standard output with -o/-out",
+            "    * an interaction name joining several interactions with commas (e.g.",
+            "      activation_PPrel,phosphorylation_PPrel) is considered if any of its",
+            "      parts is",
+            "",
+            "Exit status:",
+            "    * 0: success",
+            "    * 2: wrong command, options or arguments",
+            "    * 3: invalid input file",
+            "    * 4: no result found",
+            "    * 5: a file could not be read or written",
+            "    * progress messages, errors and warnings are printed to stderr",
+            "",
+            "For more information, see https://github.com/arnaudporet/pathrider.",
+            "",
+        },"\n"))
+    } else if usage {
+        fmt.Println(strings.Join([]string{
+            "",
+            "Usage: pathrider scc [options] <networkFile>",
+            "",
+            "Positional argument:",
+            "    * <networkFile>: the network encoded in a SIF, KGML or GraphML file, several",
+            "                     files separated by commas being merged into one",
+            "                     network",
+            "",
+            "Options:",
+            "    * -w/-weighted: the last column of the network file gives the weight of",
+            "                    the interactions (source interaction target weight),",
+            "                    weights being positive numbers, the condensation being",
+            "                    unweighted (default: not used by default)",
+            "    * -weight-file <file>: read the weights of the interactions from a TSV",
+            "                           file (source interaction target weight, or source",
+            "                           target weight for all the interactions of an",
+            "                           edge) (default: not used by default)",
+            "    * -confidence: the weights are confidence scores between 0 and 1 (e.g.",
+            "                   STRING or OmniPath scores) rather than lengths, the",
+            "                   length of an interaction being -log(score) so that the",
+            "                   shortest paths are the most confident ones (requires",
+            "                   -w/-weighted or -weight-file) (default: not used by",
+            "                   default)",
+            "    * -min-weight <float>: do not consider the interactions weighing less than",
+            "                           the given weight, namely the least confident ones",
+            "                           with -confidence, interactions without weight",
+            "                           weighing 1 (default: not used by default)",
+            "    * -include-interaction <pattern>: only consider the interactions whose name",
+            "                                      matches pattern, namely a literal name, a",
+            "                                      glob (e.g. activation_*), a regular",
+            "                                      expression between slashes (e.g.",
+            "                                      /^(in|ac)/) or @file for the patterns",
+            "                                      listed in file (one pattern per line),",
+            "                                      can be given several times (default: not",
+            "                                      used by default)",
+            "    * -exclude-interaction <pattern>: do not consider the interactions whose",
+            "                                      name matches pattern, as above (default:",
+            "                                      not used by default)",
+            "    * -b/-blacklist <file>: a file containing a list of nodes to be blacklisted",
+            "                            (one node per line), the paths containing such nodes",
+            "                            will not be considered (default: not used by",
+            "                            default)",
+            "    * -e/-blacklist-edges <file>: a file containing a list of edges to be",
+            "                                  blacklisted, either as SIF lines (source",
+            "                                  interaction target) or as source/target",
+            "                                  pairs (source target, for all the",
+            "                                  interactions of the edge), the paths",
+            "                                  containing such edges will not be",
+            "                                  considered (default: not used by default)",
+            "    * -interaction-attribute <name>: the edge attribute giving the interaction",
+            "                                     names in GraphML network files (default:",
+            "                                     interaction)",
+            "    * -map <file>: a TSV synonym table (one namespace per column, synonyms",
+            "                   separated by \"|\") mapping the identifiers of the",
+            "                   network and of the node files to node names (default:",
+            "                   not used by default)",
+            "    * -map-output <namespace>: the namespace (column) of -map naming the",
+            "                               nodes, in the output files too (default:",
+            "                               the first column)",
+            "    * -c/-comments: skip the lines of the network file starting with \"#\"",
+            "                    (default: not used by default)",
+            "    * -o/-out <file>: the output SIF file, or GraphML file if ending with",
+            "                      .graphml (default: out.sif)",
+            "    * -q/-quiet: do not print progress messages (default: not used by",
+            "                 default)",
+            "    * -v/-verbose: also print the size of the network and of the results",
+            "                   (default: not used by default)",
+            "    * -u/-usage: print usage only",
+            "    * -h/-help: print help",
+            "",
+            "Output files (unless changed with -o/-out):",
+            "    * out.sif: a SIF file encoding the condensation of the network, namely its",
+            "               strongly connected components linked by their interactions,",
+            "               the cyclic components being named scc1, scc2, ... and the",
+            "               acyclic ones after their node",
+            "    * out-components.tsv: a TSV file giving, for each node of the network, its",
+            "                          strongly connected component and the size of this",
+            "                          component",
+            "    * out-mapping.tsv: a TSV file listing the identifiers kept as is because",
+            "                       they are ambiguous or unmapped (requires -map)",
+            "",
+        },"\n"))
+    } else if (outFile!="-") && (filepath.Ext(outFile)!=".sif") && (filepath.Ext(outFile)!=".graphml") {
+        Error(ExitUsage,"pathrider scc: "+outFile+": the output network file must have the \".sif\" or \".graphml\" file extension")
+    } else if confidence && !weighted && (weightFile=="") {
+        Error(ExitUsage,"pathrider scc: -confidence requires weights, expecting -w/-weighted or -weight-file")
+    } else if len(flagSet.Args())!=1 {
+        Error(ExitUsage,"pathrider scc: wrong number of positional arguments, expecting: <networkFile>")
+    } else if !StdinOnce(append(flagSet.Args(),mapFile,blackFile,blackEdgeFile,weightFile)) {
+        Error(ExitUsage,"pathrider scc: the standard input (-) can be read only once")
+    } else {
+        args=flagSet.Args()
+        net,mapping,_,err1=LoadNetwork("scc",args[0],NetworkOptions{
+            Read:network.ReadOptions{SkipComments:skipComments,Weighted:weighted,InteractionAttribute:interactionAttribute},
+            MapFile:mapFile,
+            MapOutput:mapOutput,
+            WeightFile:weightFile,
+            BlackFile:blackFile,
+            BlackEdgeFile:blackEdgeFile,
+            Confidence:confidence,
+            MinWeight:minWeight,
+            Includes:includes,
+            Excludes:excludes,
+        })
+        if err1==nil {
+            if mapping!=nil {
+                err1=ReportMapping("scc",mapping,OutFile(outFile,"-mapping",".tsv"))
+            }
+            if err1==nil {
+                Progress("computing strongly connected components")
+                err1=WriteComponents("scc",net,OutFile(outFile,"-components",".tsv"),outFile)
+                if err1!=nil {
+                    Error(ExitIO,"pathrider scc: "+err1.Error())
+                }
+            }
+        }
+    }
+}
@@ -0,0 +1,180 @@
+// Copyright (C) 2019-2020 Arnaud Poret
+// This work is licensed under the GNU General Public License.
+// To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
+package main
+import (
+    "flag"
+    "fmt"
+    "github.com/arnaudporet/pathrider/network"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+)
+func Stats() {
+    var (
+        err error
+        help,usage,weighted,skipComments,writeJSON,diameter bool
+        outFile,interactionAttribute,jsonFile string
+        args []string
+        net *network.Network
+        stats network.Stats
+        flagSet *flag.FlagSet
+    )
+    flagSet=flag.NewFlagSet("",flag.ContinueOnError)
+    flagSet.Usage=func() {}
+    flagSet.BoolVar(&help,"help",false,"")
+    flagSet.BoolVar(&help,"h",false,"")
+    flagSet.BoolVar(&usage,"usage",false,"")
+    flagSet.BoolVar(&usage,"u",false,"")
+    flagSet.BoolVar(&quiet,"quiet",false,"")
+    flagSet.BoolVar(&quiet,"q",false,"")
+    flagSet.BoolVar(&verbose,"verbose",false,"")
+    flagSet.BoolVar(&verbose,"v",false,"")
+    flagSet.BoolVar(&writeJSON,"json",false,"")
+    flagSet.BoolVar(&diameter,"diameter",false,"")
+    flagSet.StringVar(&outFile,"out","out.txt","")
+    flagSet.StringVar(&outFile,"o","out.txt","")
+    flagSet.BoolVar(&weighted,"weighted",false,"")
+    flagSet.BoolVar(&weighted,"w",false,"")
+    flagSet.StringVar(&interactionAttribute,"interaction-attribute","","")
+    flagSet.BoolVar(&skipComments,"comments",false,"")
+    flagSet.BoolVar(&skipComments,"c",false,"")
+    err=flagSet.Parse(os.Args[2:])
+    if err!=nil {
+        Error(ExitUsage,"pathrider stats: "+err.Error())
+    } else if help {
+        fmt.Println(strings.Join([]string{
+            "",
+            "Summarise a network.",
+            "",
+            "Typical use is to get an overview of a network before querying it: its",
+            "size, its interactions, its degree distributions, its source and sink nodes,",
+            "its connected components and its diameter.",
+            "",
+            "Usage: pathrider stats [options] <networkFile>",
+            "",
+            "Positional argument:",
+            "    * <networkFile>: the network encoded in a SIF, KGML or GraphML file, several",
+            "                     files separated by commas being merged into one",
+            "                     network",
+            "",
+            "Options:",
+            "    * -diameter: also compute the diameter of the network (default: not used",
+            "                 by default)",
+            "    * -json: also write the statistics in JSON (default: not used by default)",
+            "    * -w/-weighted: the last column of the network file gives the weight of",
+            "                    the interactions (source interaction target weight),",
+            "                    weights being positive numbers (default: not used by",
+            "                    default)",
+            "    * -interaction-attribute <name>: the edge attribute giving the interaction",
+            "                                     names in GraphML network files (default:",
+            "                                     interaction)",
+            "    * -c/-comments: skip the lines of the network file starting with \"#\"",
+            "                    (default: not used by default)",
+            "    * -o/-out <file>: the output text file (default: out.txt)",
+            "    * -q/-quiet: do not print progress messages (default: not used by",
+            "                 default)",
+            "    * -v/-verbose: also print the size of the network and of the results",
+            "                   (default: not used by default)",
+            "    * -u/-usage: print usage only",
+            "    * -h/-help: print help",
+            "",
+            "Output file(s) (unless changed with -o/-out):",
+            "    * out.txt: a text file giving the numbers of nodes, edges (source/target",
+            "               pairs), interactions, self-loops, duplicate interactions",
+            "               removed while reading, sources (nodes without predecessors),",
+            "               sinks (nodes without successors), connected components",
+            "               (ignoring edge directions) and the size of the largest one,",
+            "               and the diameter (length of the longest shortest path, in",
+            "               number of edges, requires -diameter), followed by the",
+            "               frequencies of the interaction names, the in-degree and",
+            "               out-degree distributions, the sources and the sinks",
+            "    * out.json: the same statistics in JSON (requires -json)",
+            "",
+            "Cautions:",
+            "    * the network must be in the SIF, KGML or GraphML file format (see the",
+            "      readme file of pathrider)",
+            "    * lines listing several targets (source interaction target1 target2 ...)",
+            "      are expanded into one edge per target",
+            "    * lines made of a single node add this node without any edge",
+            "    * edges are assumed to be directed",
+            "    * - stands for the standard input in place of an input file (once at",
+            "      most) and for the standard output with -o/-out",
+            "    * computing the diameter requires a breadth-first search from each node,",
+            "      which is slow for large networks (time proportional to",
+            "      nodes*(nodes+edges))",
+            "",
+            "Exit status:",
+            "    * 0: success",
+            "    * 2: wrong command, options or arguments",
+            "    * 3: invalid input file",
+            "    * 5: a file could not be read or written",
+            "    * progress messages, errors and warnings are printed to stderr",
+            "",
+            "For more information, see https://github.com/arnaudporet/pathrider.",
+            "",
+        },"\n"))
+    } else if usage {
+        fmt.Println(strings.Join([]string{
+            "",
+            "Usage: pathrider stats [options] <networkFile>",
+            "",
+            "Positional argument:",
+            "    * <networkFile>: the network encoded in a SIF, KGML or GraphML file, several",
+            "                     files separated by commas being merged into one",
+            "                     network",
+            "",
+            "Options:",
+            "    * -diameter: also compute the diameter of the network (default: not used",
+            "                 by default)",
+            "    * -json: also write the statistics in JSON (default: not used by default)",
+            "    * -w/-weighted: the last column of the network file gives the weight of",
+            "                    the interactions (source interaction target weight),",
+            "                    weights being positive numbers (default: not used by",
+            "                    default)",
+            "    * -interaction-attribute <name>: the edge attribute giving the interaction",
+            "                                     names in GraphML network files (default:",
+            "                                     interaction)",
+            "    * -c/-comments: skip the lines of the network file starting with \"#\"",
+            "                    (default: not used by default)",
+            "    * -o/-out <file>: the output text file (default: out.txt)",
+            "    * -q/-quiet: do not print progress messages (default: not used by",
+            "                 default)",
+            "    * -v/-verbose: also print the size of the network and of the results",
+            "                   (default: not used by default)",
+            "    * -u/-usage: print usage only",
+            "    * -h/-help: print help",
+            "",
+        },"\n"))
+    } else if (outFile!="-") && (filepath.Ext(outFile)!=".txt") {
+        Error(ExitUsage,"pathrider stats: "+outFile+": the output text file must have the \".txt\" file extension")
+    } else if len(flagSet.Args())!=1 {
+        Error(ExitUsage,"pathrider stats: wrong number of positional arguments, expecting: <networkFile>")
+    } else if !StdinOnce(flagSet.Args()) {
+        Error(ExitUsage,"pathrider stats: the standard input (-) can be read only once")
+    } else {
+        args=flagSet.Args()
+        Progress("reading network: "+args[0])
+        net,err=network.ReadFiles(strings.Split(args[0],","),network.ReadOptions{SkipComments:skipComments,Weighted:weighted,InteractionAttribute:interactionAttribute})
+        if err!=nil {
+            Error(ErrorCode(err),"pathrider stats: "+args[0]+": "+err.Error())
+        } else {
+            Detail("network: "+strconv.Itoa(net.NumNodes())+" nodes, "+strconv.Itoa(net.NumEdges())+" edges")
+            Progress("computing statistics")
+            stats=net.Stats(diameter)
+            Progress("writing statistics: "+outFile)
+            err=network.WriteStats(outFile,stats)
+            if err!=nil {
+                Error(ExitIO,"pathrider stats: "+outFile+": "+err.Error())
+            } else if writeJSON {
+                jsonFile=OutFile(outFile,"",".json")
+                Progress("writing statistics: "+jsonFile)
+                err=network.WriteStatsJSON(jsonFile,stats)
+                if err!=nil {
+                    Error(ExitIO,"pathrider stats: "+jsonFile+": "+err.Error())
+                }
+            }
+        }
+    }
+}
@@ -5,6 +5,7 @@ package main
 import (
     "flag"
     "fmt"
+    "github.com/arnaudporet/pathrider/network"
     "math"
     "os"
     "path/filepath"
@@ -13,14 +14,13 @@ import (
 func Stream() {
     var (
         err error
-        help,usage,getTerminal bool
-        depth float64
-        outFile,outFilePath,outFileBase,blackFile string
-        args,nodes,blackNodes,seeds,termNodes []string
-        edges,ward [][]string
-        nodeSP map[string][]string
-        edgeNames map[string]map[string][]string
-        edgeSP map[string]map[string][][]string
+        help,usage,weighted,confidence,getProvenance,getComponents,getTerminal,skipComments,writeDOT,writeSVG bool
+        depth,minWeight float64
+        outFile,interactionAttribute,mapFile,mapOutput,dirFile,stream,blackFile,blackEdgeFile,weightFile,node string
+        args,blackNeighbours,seeds,termNodes []string
+        net,ward,upward,downward *network.Network
+        mapping *network.Mapping
+        includes,excludes StringList
         flagSet *flag.FlagSet
     )
     flagSet=flag.NewFlagSet("",flag.ContinueOnError)
@@ -29,17 +29,41 @@ func Stream() {
     flagSet.BoolVar(&help,"h",false,"")
     flagSet.BoolVar(&usage,"usage",false,"")
     flagSet.BoolVar(&usage,"u",false,"")
+    flagSet.BoolVar(&quiet,"quiet",false,"")
+    flagSet.BoolVar(&quiet,"q",false,"")
+    flagSet.BoolVar(&verbose,"verbose",false,"")
+    flagSet.BoolVar(&verbose,"v",false,"")
     flagSet.StringVar(&outFile,"out","out.sif","")
     flagSet.StringVar(&outFile,"o","out.sif","")
     flagSet.BoolVar(&getTerminal,"terminal",false,"")
     flagSet.BoolVar(&getTerminal,"t",false,"")
     flagSet.StringVar(&blackFile,"blacklist","","")
     flagSet.StringVar(&blackFile,"b","","")
+    flagSet.StringVar(&blackEdgeFile,"blacklist-edges","","")
+    flagSet.StringVar(&blackEdgeFile,"e","","")
+    flagSet.BoolVar(&weighted,"weighted",false,"")
+    flagSet.BoolVar(&weighted,"w",false,"")
+    flagSet.StringVar(&weightFile,"weight-file","","")
+    flagSet.BoolVar(&confidence,"confidence",false,"")
+    flagSet.Float64Var(&minWeight,"min-weight",math.NaN(),"")
+    flagSet.Var(&includes,"include-interaction","")
+    flagSet.Var(&excludes,"exclude-interaction","")
+    flagSet.BoolVar(&getProvenance,"provenance",false,"")
+    flagSet.BoolVar(&getProvenance,"p",false,"")
+    flagSet.BoolVar(&getComponents,"scc",false,"")
+    flagSet.StringVar(&interactionAttribute,"interaction-attribute","","")
+    flagSet.StringVar(&mapFile,"map","","")
+    flagSet.StringVar(&mapOutput,"map-output","","")
+    flagSet.BoolVar(&skipComments,"comments",false,"")
+    flagSet.BoolVar(&skipComments,"c",false,"")
+    flagSet.BoolVar(&writeDOT,"graphviz",false,"")
+    flagSet.BoolVar(&writeDOT,"g",false,"")
+    flagSet.BoolVar(&writeSVG,"svg",false,"")
     flagSet.Float64Var(&depth,"depth",math.NaN(),"")
     flagSet.Float64Var(&depth,"d",math.NaN(),"")
     err=flagSet.Parse(os.Args[2:])
     if err!=nil {
-        fmt.Println("Error: pathrider stream: "+err.Error())
+        Error(ExitUsage,"pathrider stream: "+err.Error())
     } else if help {
         fmt.Println(strings.Join([]string{
             "",
@@ -52,22 +76,93 @@ func Stream() {
             "Usage: pathrider stream [options] <networkFile> <seedFile> <direction>",
             "",
             "Positional arguments:",
-            "    * <networkFile>: the network encoded in a SIF file",
+            "    * <networkFile>: the network encoded in a SIF, KGML or GraphML file, several",
+            "                     files separated by commas being merged into one",
+            "                     network",
             "    * <seedFile>: the seed nodes listed in a file (one node per line)",
-            "    * <direction>: follow the up stream (up) or the down stream (down)",
+            "    * <direction>: follow the up stream (up), the down stream (down) or both",
+            "                   (both)",
             "",
             "Options:",
             "    * -t/-terminal: also find the terminal nodes reachable from the seed nodes,",
             "                    namely the nodes having no predecessors in case of",
             "                    upstreaming, or the nodes having no successors in case of",
-            "                    downstreaming (default: not used by default)",
+            "                    downstreaming, or both in case of both, in separate files",
+            "                    (default: not used by default)",
             "    * -d/-depth <int>: the maximal depth when up/down streaming from the seed",
             "                       nodes (default: not used by default)",
+            "    * -w/-weighted: the last column of the network file gives the weight of",
+            "                    the interactions (source interaction target weight),",
+            "                    weights being positive numbers, preserved in the output",
+            "                    files (default: not used by default)",
+            "    * -weight-file <file>: read the weights of the interactions from a TSV",
+            "                           file (source interaction target weight, or source",
+            "                           target weight for all the interactions of an",
+            "                           edge) (default: not used by default)",
+            "    * -confidence: the weights are confidence scores between 0 and 1 (e.g.",
+            "                   STRING or OmniPath scores) rather than lengths, the",
+            "                   length of an interaction being -log(score) so that the",
+            "                   shortest paths are the most confident ones (requires",
+            "                   -w/-weighted or -weight-file) (default: not used by",
+            "                   default)",
+            "    * -min-weight <float>: do not consider the interactions weighing less than",
+            "                           the given weight, namely the least confident ones",
+            "                           with -confidence, interactions without weight",
+            "                           weighing 1 (default: not used by default)",
+            "    * -include-interaction <pattern>: only consider the interactions whose name",
+            "                                      matches pattern, namely a literal name, a",
+            "                                      glob (e.g. activation_*), a regular",
+            "                                      expression between slashes (e.g.",
+            "                                      /^(in|ac)/) or @file for the patterns",
+            "                                      listed in file (one pattern per line),",
+            "                                      can be given several times (default: not",
+            "                                      used by default)",
+            "    * -exclude-interaction <pattern>: do not consider the interactions whose",
+            "                                      name matches pattern, as above (default:",
+            "                                      not used by default)",
             "    * -b/-blacklist <file>: a file containing a list of nodes to be blacklisted",
             "                            (one node per line), the paths containing such nodes",
             "                            will not be considered (default: not used by",
             "                            default)",
-            "    * -o/-out <file>: the output SIF file (default: out.sif)",
+            "    * -e/-blacklist-edges <file>: a file containing a list of edges to be",
+            "                                  blacklisted, either as SIF lines (source",
+            "                                  interaction target) or as source/target",
+            "                                  pairs (source target, for all the",
+            "                                  interactions of the edge), the paths",
+            "                                  containing such edges will not be",
+            "                                  considered (default: not used by default)",
+            "    * -p/-provenance: also write, for each output edge and node, the seed",
+            "                      nodes it is upstream or downstream of (default: not",
+            "                      used by default)",
+            "    * -scc: also write the strongly connected components of the output paths",
+            "            and their condensation (default: not used by default)",
+            "    * -interaction-attribute <name>: the edge attribute giving the interaction",
+            "                                     names in GraphML network files (default:",
+            "                                     interaction)",
+            "    * -map <file>: a TSV synonym table (one namespace per column, synonyms",
+            "                   separated by \"|\") mapping the identifiers of the",
+            "                   network and of the node files to node names (default:",
+            "                   not used by default)",
+            "    * -map-output <namespace>: the namespace (column) of -map naming the",
+            "                               nodes, in the output files too (default:",
+            "                               the first column)",
+            "    * -c/-comments: skip the lines of the network file starting with \"#\"",
+            "                    (default: not used by default)",
+            "    * -g/-graphviz: also write the output paths in the DOT file format of",
+            "                    Graphviz, highlighting the seed and terminal nodes",
+            "                    (default: not used by default)",
+            "    * -svg: also draw the output paths in a standalone SVG file and in an",
+            "            interactive HTML page, with a layered layout not requiring",
+            "            Graphviz (default: not used by default)",
+            "    * -o/-out <file>: the output SIF file, or GraphML (.graphml),",
+            "                      cytoscape.js (.json) or CX2 (.cx2) file according to",
+            "                      its extension, the last two being annotated for",
+            "                      Cytoscape (node roles, distances, shortest paths)",
+            "                      (default: out.sif)",
+            "    * -q/-quiet: do not print progress messages (default: not used by",
+            "                 default)",
+            "    * -v/-verbose: also print the size of the network and of the results",
+            "                   (default: not used by default)",
             "    * -u/-usage: print usage only",
             "    * -h/-help: print help",
             "",
@@ -76,13 +171,56 @@ func Stream() {
             "               the seed nodes in the network",
             "    * out-terminal.txt: a file listing the upstream/downstream terminal nodes",
             "                        reachable from the seed nodes in the network",
-            "                        (requires -t/-terminal)",
+            "                        (requires -t/-terminal, up or down)",
+            "    * out-terminal-up.txt, out-terminal-down.txt: the same for the upstream",
+            "                                                  and the downstream terminal",
+            "                                                  nodes in case of both",
+            "                                                  (requires -t/-terminal)",
+            "    * out-direction.tsv: a TSV file telling, for each edge of out.sif, whether",
+            "                         it is upstream (up), downstream (down) or both",
+            "                         (both) of the seed nodes (requires both)",
+            "    * out-provenance.tsv: a TSV file giving, for each edge of out.sif, the",
+            "                          seed nodes it is upstream or downstream of and",
+            "                          their number (requires -p/-provenance)",
+            "    * out-provenance-nodes.tsv: the same for the nodes of out.sif (requires",
+            "                                -p/-provenance)",
+            "    * out-components.tsv: a TSV file giving, for each node of out.sif, its",
+            "                          strongly connected component and the size of this",
+            "                          component (requires -scc)",
+            "    * out-condensed.sif: a SIF file encoding the condensation of out.sif,",
+            "                         namely its strongly connected components linked by",
+            "                         their interactions, the cyclic components being",
+            "                         named scc1, scc2, ... (requires -scc)",
+            "    * out.dot: the same paths in the DOT file format, to be rendered with",
+            "               Graphviz (e.g. dot -Tsvg) (requires -g/-graphviz)",
+            "    * out.svg: the same paths drawn with a layered layout (requires -svg)",
+            "    * out.html: the same drawing in an interactive HTML page (requires -svg)",
+            "    * out-mapping.tsv: a TSV file listing the identifiers kept as is because",
+            "                       they are ambiguous or unmapped (requires -map)",
             "",
             "Cautions:",
-            "    * the network must be in the SIF file format (see the readme file of",
-            "      pathrider)",
+            "    * the network must be in the SIF, KGML or GraphML file format (see the",
+            "      readme file of pathrider)",
+            "    * lines listing several targets (source interaction target1 target2 ...)",
+            "      are expanded into one edge per target",
+            "    * lines made of a single node add this node without any edge",
             "    * edge duplicates are automatically removed",
             "    * edges are assumed to be directed",
+            "    * - stands for the standard input in place of an input file (once at",
+            "      most) and for the standard output with -o/-out",
+            "    * an interaction name joining several interactions with commas (e.g.",
+            "      activation_PPrel,phosphorylation_PPrel) is considered if any of its",
+            "      parts is",
+            "    * with both, a direction without paths or without terminal nodes is only",
+            "      warned about, its terminal node file being not written",
+            "",
+            "Exit status:",
+            "    * 0: success",
+            "    * 2: wrong command, options or arguments",
+            "    * 3: invalid input file",
+            "    * 4: no result found",
+            "    * 5: a file could not be read or written",
+            "    * progress messages, errors and warnings are printed to stderr",
             "",
             "For more information, see https://github.com/arnaudporet/pathrider.",
             "",
@@ -93,22 +231,93 @@ func Stream() {
             "Usage: pathrider stream [options] <networkFile> <seedFile> <direction>",
             "",
             "Positional arguments:",
-            "    * <networkFile>: the network encoded in a SIF file",
+            "    * <networkFile>: the network encoded in a SIF, KGML or GraphML file, several",
+            "                     files separated by commas being merged into one",
+            "                     network",
             "    * <seedFile>: the seed nodes listed in a file (one node per line)",
-            "    * <direction>: follow the up stream (up) or the down stream (down)",
+            "    * <direction>: follow the up stream (up), the down stream (down) or both",
+            "                   (both)",
             "",
             "Options:",
             "    * -t/-terminal: also find the terminal nodes reachable from the seed nodes,",
             "                    namely the nodes having no predecessors in case of",
             "                    upstreaming, or the nodes having no successors in case of",
-            "                    downstreaming (default: not used by default)",
+            "                    downstreaming, or both in case of both, in separate files",
+            "                    (default: not used by default)",
             "    * -d/-depth <int>: the maximal depth when up/down streaming from the seed",
             "                       nodes (default: not used by default)",
+            "    * -w/-weighted: the last column of the network file gives the weight of",
+            "                    the interactions (source interaction target weight),",
+            "                    weights being positive numbers, preserved in the output",
+            "                    files (default: not used by default)",
+            "    * -weight-file <file>: read the weights of the interactions from a TSV",
+            "                           file (source interaction target weight, or source",
+            "                           target weight for all the interactions of an",
+            "                           edge) (default: not used by default)",
+            "    * -confidence: the weights are confidence scores between 0 and 1 (e.g.",
+            "                   STRING or OmniPath scores) rather than lengths, the",
+            "                   length of an interaction being -log(score) so that the",
+            "                   shortest paths are the most confident ones (requires",
+            "                   -w/-weighted or -weight-file) (default: not used by",
+            "                   default)",
+            "    * -min-weight <float>: do not consider the interactions weighing less than",
+            "                           the given weight, namely the least confident ones",
+            "                           with -confidence, interactions without weight",
+            "                           weighing 1 (default: not used by default)",
+            "    * -include-interaction <pattern>: only consider the interactions whose name",
+            "                                      matches pattern, namely a literal name, a",
+            "                                      glob (e.g. activation_*), a regular",
+            "                                      expression between slashes (e.g.",
+            "                                      /^(in|ac)/) or @file for the patterns",
+            "                                      listed in file (one pattern per line),",
+            "                                      can be given several times (default: not",
+            "                                      used by default)",
+            "    * -exclude-interaction <pattern>: do not consider the interactions whose",
+            "                                      name matches pattern, as above (default:",
+            "                                      not used by default)",
             "    * -b/-blacklist <file>: a file containing a list of nodes to be blacklisted",
             "                            (one node per line), the paths containing such nodes",
             "                            will not be considered (default: not used by",
             "                            default)",
-            "    * -o/-out <file>: the output SIF file (default: out.sif)",
+            "    * -e/-blacklist-edges <file>: a file containing a list of edges to be",
+            "                                  blacklisted, either as SIF lines (source",
+            "                                  interaction target) or as source/target",
+            "                                  pairs (source target, for all the",
+            "                                  interactions of the edge), the paths",
+            "                                  containing such edges will not be",
+            "                                  considered (default: not used by default)",
+            "    * -p/-provenance: also write, for each output edge and node, the seed",
+            "                      nodes it is upstream or downstream of (default: not",
+            "                      used by default)",
+            "    * -scc: also write the strongly connected components of the output paths",
+            "            and their condensation (default: not used by default)",
+            "    * -interaction-attribute <name>: the edge attribute giving the interaction",
+            "                                     names in GraphML network files (default:",
+            "                                     interaction)",
+            "    * -map <file>: a TSV synonym table (one namespace per column, synonyms",
+            "                   separated by \"|\") mapping the identifiers of the",
+            "                   network and of the node files to node names (default:",
+            "                   not used by default)",
+            "    * -map-output <namespace>: the namespace (column) of -map naming the",
+            "                               nodes, in the output files too (default:",
+            "                               the first column)",
+            "    * -c/-comments: skip the lines of the network file starting with \"#\"",
+            "                    (default: not used by default)",
+            "    * -g/-graphviz: also write the output paths in the DOT file format of",
+            "                    Graphviz, highlighting the seed and terminal nodes",
+            "                    (default: not used by default)",
+            "    * -svg: also draw the output paths in a standalone SVG file and in an",
+            "            interactive HTML page, with a layered layout not requiring",
+            "            Graphviz (default: not used by default)",
+            "    * -o/-out <file>: the output SIF file, or GraphML (.graphml),",
+            "                      cytoscape.js (.json) or CX2 (.cx2) file according to",
+            "                      its extension, the last two being annotated for",
+            "                      Cytoscape (node roles, distances, shortest paths)",
+            "                      (default: out.sif)",
+            "    * -q/-quiet: do not print progress messages (default: not used by",
+            "                 default)",
+            "    * -v/-verbose: also print the size of the network and of the results",
+            "                   (default: not used by default)",
             "    * -u/-usage: print usage only",
             "    * -h/-help: print help",
             "",
@@ -117,79 +326,135 @@ func Stream() {
             "               the seed nodes in the network",
             "    * out-terminal.txt: a file listing the upstream/downstream terminal nodes",
             "                        reachable from the seed nodes in the network",
-            "                        (requires -t/-terminal)",
+            "                        (requires -t/-terminal, up or down)",
+            "    * out-terminal-up.txt, out-terminal-down.txt: the same for the upstream",
+            "                                                  and the downstream terminal",
+            "                                                  nodes in case of both",
+            "                                                  (requires -t/-terminal)",
+            "    * out-direction.tsv: a TSV file telling, for each edge of out.sif, whether",
+            "                         it is upstream (up), downstream (down) or both",
+            "                         (both) of the seed nodes (requires both)",
+            "    * out-provenance.tsv: a TSV file giving, for each edge of out.sif, the",
+            "                          seed nodes it is upstream or downstream of and",
+            "                          their number (requires -p/-provenance)",
+            "    * out-provenance-nodes.tsv: the same for the nodes of out.sif (requires",
+            "                                -p/-provenance)",
+            "    * out-components.tsv: a TSV file giving, for each node of out.sif, its",
+            "                          strongly connected component and the size of this",
+            "                          component (requires -scc)",
+            "    * out-condensed.sif: a SIF file encoding the condensation of out.sif,",
+            "                         namely its strongly connected components linked by",
+            "                         their interactions, the cyclic components being",
+            "                         named scc1, scc2, ... (requires -scc)",
+            "    * out.dot: the same paths in the DOT file format, to be rendered with",
+            "               Graphviz (e.g. dot -Tsvg) (requires -g/-graphviz)",
+            "    * out.svg: the same paths drawn with a layered layout (requires -svg)",
+            "    * out.html: the same drawing in an interactive HTML page (requires -svg)",
+            "    * out-mapping.tsv: a TSV file listing the identifiers kept as is because",
+            "                       they are ambiguous or unmapped (requires -map)",
             "",
         },"\n"))
-    } else if filepath.Ext(outFile)!=".sif" {
-        fmt.Println("Error: pathrider stream: "+outFile+": the output SIF file must have the \".sif\" file extension")
+    } else if (outFile!="-") && !IsInList([]string{".sif",".graphml",".json",".cx2"},filepath.Ext(outFile)) {
+        Error(ExitUsage,"pathrider stream: "+outFile+": the output network file must have the \".sif\", \".graphml\", \".json\" or \".cx2\" file extension")
     } else if !math.IsNaN(depth) && ((math.Round(depth)!=depth) || (depth<1)) {
-        fmt.Println("Error: pathrider stream: depth must be a positive integer")
+        Error(ExitUsage,"pathrider stream: depth must be a positive integer")
+    } else if confidence && !weighted && (weightFile=="") {
+        Error(ExitUsage,"pathrider stream: -confidence requires weights, expecting -w/-weighted or -weight-file")
     } else if len(flagSet.Args())!=3 {
-        fmt.Println("Error: pathrider stream: wrong number of positional arguments, expecting: <networkFile> <seedFile> <direction>")
-    } else if (flagSet.Arg(2)!="up") && (flagSet.Arg(2)!="down") {
-        fmt.Println("Error: pathrider stream: "+flagSet.Arg(2)+": unknown direction, expecting one of: up, down")
+        Error(ExitUsage,"pathrider stream: wrong number of positional arguments, expecting: <networkFile> <seedFile> <direction>")
+    } else if !StdinOnce([]string{flagSet.Arg(0),flagSet.Arg(1),mapFile,blackFile,blackEdgeFile,weightFile}) {
+        Error(ExitUsage,"pathrider stream: the standard input (-) can be read only once")
+    } else if (flagSet.Arg(2)!="up") && (flagSet.Arg(2)!="down") && (flagSet.Arg(2)!="both") {
+        Error(ExitUsage,"pathrider stream: "+flagSet.Arg(2)+": unknown direction, expecting one of: up, down, both")
     } else {
         args=flagSet.Args()
-        fmt.Println("reading network: "+args[0])
-        nodes,edges,edgeNames,err=ReadNetwork(args[0])
-        if err!=nil {
-            fmt.Println("Error: pathrider stream: "+args[0]+": "+err.Error())
+        if args[2]=="both" {
+            stream="up/downstream"
         } else {
-            if blackFile!="" {
-                fmt.Println("reading blacklist: "+blackFile)
-                blackNodes,err=ReadNodes(blackFile,nodes)
-                if err!=nil {
-                    fmt.Println("Error: pathrider stream: "+blackFile+": "+err.Error())
-                } else {
-                    fmt.Println("blacklisting nodes")
-                    nodes,edges,edgeNames,err=RmNodes(edges,edgeNames,blackNodes)
-                    if err!=nil {
-                        fmt.Println("Error: pathrider stream: "+blackFile+": "+err.Error())
-                    }
-                }
+            stream=args[2]+"stream"
+        }
+        net,mapping,blackNeighbours,err=LoadNetwork("stream",args[0],NetworkOptions{
+            Read:network.ReadOptions{SkipComments:skipComments,Weighted:weighted,InteractionAttribute:interactionAttribute},
+            MapFile:mapFile,
+            MapOutput:mapOutput,
+            WeightFile:weightFile,
+            BlackFile:blackFile,
+            BlackEdgeFile:blackEdgeFile,
+            Confidence:confidence,
+            MinWeight:minWeight,
+            Includes:includes,
+            Excludes:excludes,
+        })
+        if err==nil {
+            Progress("reading seed nodes: "+args[1])
+            seeds,err=net.ReadNodes(args[1])
+            if err!=nil {
+                Error(ErrorCode(err),"pathrider stream: "+args[1]+": "+err.Error())
+            } else if mapping!=nil {
+                err=ReportMapping("stream",mapping,OutFile(outFile,"-mapping",".tsv"))
             }
             if err==nil {
-                fmt.Println("reading seed nodes: "+args[1])
-                seeds,err=ReadNodes(args[1],nodes)
+                Progress(stream+"ing seed nodes")
+                ward,err=net.Stream(seeds,args[2],depth)
                 if err!=nil {
-                    fmt.Println("Error: pathrider stream: "+args[1]+": "+err.Error())
+                    Error(ExitUsage,"pathrider stream: "+err.Error())
+                } else if ward.NumEdges()==0 {
+                    Empty("pathrider stream: "+args[1]+": no "+stream+" paths found")
                 } else {
-                    fmt.Println(args[2]+"streaming seed nodes")
                     if args[2]=="up" {
-                        nodeSP,edgeSP=GetPredecessors(edges)
-                        ward=BackwardEdges(seeds,nodeSP,edgeSP,depth)
+                        termNodes=ward.Roots()
                     } else if args[2]=="down" {
-                        nodeSP,edgeSP=GetSuccessors(edges)
-                        ward=ForwardEdges(seeds,nodeSP,edgeSP,depth)
+                        termNodes=ward.Leaves()
+                    } else if args[2]=="both" {
+                        upward,_=net.Stream(seeds,"up",depth)
+                        downward,_=net.Stream(seeds,"down",depth)
+                        if upward.NumEdges()==0 {
+                            Warning("pathrider stream: "+args[1]+": no upstream paths found")
+                        }
+                        if downward.NumEdges()==0 {
+                            Warning("pathrider stream: "+args[1]+": no downstream paths found")
+                        }
+                        termNodes=upward.Roots()
+                        for _,node=range downward.Leaves() {
+                            if !IsInList(termNodes,node) {
+                                termNodes=append(termNodes,node)
+                            }
+                        }
                     }
-                    if len(ward)==0 {
-                        fmt.Println("Warning: pathrider stream: "+args[1]+": no "+args[2]+"stream paths found")
-                    } else {
-                        fmt.Println("writing "+args[2]+"stream paths: "+outFile)
-                        err=WriteNetwork(outFile,ward,edgeNames)
+                    err=WriteResult("stream",stream+" paths",ward,outFile,writeDOT,writeSVG,network.Highlight{Seeds:seeds,Terminals:termNodes,Direction:args[2],BlackNeighbours:blackNeighbours})
+                    if (err==nil) && (args[2]=="both") {
+                        dirFile=OutFile(outFile,"-direction",".tsv")
+                        Progress("writing edge directions: "+dirFile)
+                        err=ward.WriteEdgeTable(dirFile,[]string{"direction"},EdgeDirections(ward,upward,downward))
                         if err!=nil {
-                            fmt.Println("Error: pathrider stream: "+outFile+": "+err.Error())
-                        } else if getTerminal {
-                            fmt.Println("computing "+args[2]+"stream terminal nodes")
-                            if args[2]=="up" {
-                                nodeSP,edgeSP=GetPredecessors(ward)
-                            } else if args[2]=="down" {
-                                nodeSP,edgeSP=GetSuccessors(ward)
+                            Error(ExitIO,"pathrider stream: "+dirFile+": "+err.Error())
+                        }
+                    }
+                    if (err==nil) && getProvenance {
+                        Progress("computing provenance")
+                        err=WriteProvenance(ward,SeedProvenance(ward,seeds,args[2],depth),"seeds",OutFile(outFile,"-provenance",".tsv"),OutFile(outFile,"-provenance-nodes",".tsv"))
+                        if err!=nil {
+                            Error(ExitIO,"pathrider stream: "+err.Error())
+                        }
+                    }
+                    if (err==nil) && getComponents {
+                        Progress("computing strongly connected components")
+                        err=WriteComponents("stream",ward,OutFile(outFile,"-components",".tsv"),NetworkFile(outFile,"-condensed"))
+                        if err!=nil {
+                            Error(ExitIO,"pathrider stream: "+err.Error())
+                        }
+                    }
+                    if (err==nil) && getTerminal {
+                        Progress("computing "+stream+" terminal nodes")
+                        if args[2]=="both" {
+                            if upward.NumEdges()!=0 {
+                                err=WriteTerminal(OutFile(outFile,"-terminal-up",".txt"),"upstream",upward.Roots(),args[1])
                             }
-                            termNodes=TerminalNodes(nodeSP)
-                            if len(termNodes)==0 {
-                                fmt.Println("Warning: pathrider stream: "+args[1]+": no "+args[2]+"stream terminal nodes found")
-                            } else {
-                                outFilePath,outFileBase=filepath.Split(outFile)
-                                outFileBase=strings.TrimSuffix(outFileBase,".sif")
-                                outFileBase+="-terminal.txt"
-                                outFile=filepath.Join(outFilePath,outFileBase)
-                                fmt.Println("writing "+args[2]+"stream terminal nodes: "+outFile)
-                                err=WriteText(outFile,termNodes)
-                                if err!=nil {
-                                    fmt.Println("Error: pathrider stream: "+outFile+": "+err.Error())
-                                }
+                            if (err==nil) && (downward.NumEdges()!=0) {
+                                err=WriteTerminal(OutFile(outFile,"-terminal-down",".txt"),"downstream",downward.Leaves(),args[1])
                             }
+                        } else {
+                            err=WriteTerminal(OutFile(outFile,"-terminal",".txt"),stream,termNodes,args[1])
                         }
                     }
                 }
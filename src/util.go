@@ -1,83 +1,183 @@
 // Copyright (C) 2019-2020 Arnaud Poret
 // This work is licensed under the GNU General Public License.
 // To view a copy of this license, visit https://www.gnu.org/licenses/gpl.html.
-
-// WARNING The functions in the present file do not fully handle exceptions and
-// errors. Instead, they assume that such handling is performed upstream by
-// top-level functions of pathrider. Consequently, be careful if using them
-// "as is" outside of pathrider.
-
 package main
 import (
     "errors"
+    "fmt"
+    "github.com/arnaudporet/pathrider/network"
+    "math"
     "os"
+    "path/filepath"
+    "regexp"
+    "strconv"
     "strings"
 )
-func CopyList(list []string) []string {
-    var (
-        y []string
-    )
-    y=make([]string,len(list))
-    copy(y,list)
-    return y
+// Exit codes of pathrider: ExitUsage for wrong commands, options or arguments,
+// ExitInput for invalid input files, ExitEmpty when no result is found and
+// ExitIO when reading or writing a file fails.
+const (
+    ExitUsage=2
+    ExitInput=3
+    ExitEmpty=4
+    ExitIO=5
+)
+var (
+    exitCode int
+    quiet,verbose bool
+)
+// Progress prints a progress message to stderr, unless -quiet is used, stdout
+// being left to the outputs written to "-".
+func Progress(message string) {
+    if !quiet {
+        fmt.Fprintln(os.Stderr,message)
+    }
 }
-func CopyList2(list2 [][]string) [][]string {
-    var (
-        i int
-        y [][]string
-    )
-    y=make([][]string,len(list2))
-    for i=range list2 {
-        y[i]=CopyList(list2[i])
+// Detail prints a detailed progress message to stderr, only if -verbose is
+// used.
+func Detail(message string) {
+    if verbose && !quiet {
+        fmt.Fprintln(os.Stderr,message)
     }
-    return y
 }
-func IsInList(list []string,thatElement string) bool {
-    var (
-        found bool
-        element string
-    )
-    found=false
-    for _,element=range list {
-        if element==thatElement {
-            found=true
-            break
-        }
+// Warning prints a warning to stderr, leaving the exit code unchanged.
+func Warning(message string) {
+    fmt.Fprintln(os.Stderr,"Warning: "+message)
+}
+// Empty prints a warning to stderr reporting an empty result, the exit code
+// becoming ExitEmpty unless an error occurred.
+func Empty(message string) {
+    Warning(message)
+    if exitCode==0 {
+        exitCode=ExitEmpty
+    }
+}
+// Error prints an error to stderr and sets the exit code.
+func Error(code int,message string) {
+    fmt.Fprintln(os.Stderr,"Error: "+message)
+    if (exitCode==0) || (exitCode==ExitEmpty) {
+        exitCode=code
     }
-    return found
 }
-func IsInList2(list2 [][]string,thatList []string) bool {
+// ErrorCode returns the exit code of an error met while reading an input
+// file: ExitIO if the file could not be read, ExitInput otherwise.
+func ErrorCode(err error) int {
     var (
-        found bool
-        list []string
+        code int
+        pathErr *os.PathError
     )
-    found=false
-    for _,list=range list2 {
-        if ListEq(list,thatList) {
-            found=true
-            break
-        }
+    if errors.As(err,&pathErr) {
+        code=ExitIO
+    } else {
+        code=ExitInput
     }
-    return found
+    return code
+}
+// NetworkOptions are the options shared by the commands for reading the
+// network and preparing it before searching paths.
+type NetworkOptions struct {
+    Read network.ReadOptions
+    MapFile,MapOutput,WeightFile,BlackFile,BlackEdgeFile string
+    Confidence bool
+    MinWeight float64
+    Includes,Excludes []string
 }
-func ListEq(list1,list2 []string) bool {
+// LoadNetwork reads the network from networkFiles (separated by commas), then,
+// after options, maps its nodes, reads its weights (possibly confidence
+// scores), filters its weights and its interactions and blacklists nodes and
+// edges, reporting progress and errors as the commands do. It also returns the
+// mapping (nil without options.MapFile) and the nodes which were adjacent to
+// the blacklisted nodes.
+func LoadNetwork(command,networkFiles string,options NetworkOptions) (*network.Network,*network.Mapping,[]string,error) {
     var (
-        eq bool
-        i int
+        err error
+        blackNodes,blackNeighbours []string
+        include,exclude []*regexp.Regexp
+        blackEdges [][]string
+        net *network.Network
+        mapping *network.Mapping
     )
-    eq=true
-    if len(list1)!=len(list2) {
-        eq=false
+    Progress("reading network: "+networkFiles)
+    net,err=network.ReadFiles(strings.Split(networkFiles,","),options.Read)
+    if err!=nil {
+        Error(ErrorCode(err),"pathrider "+command+": "+networkFiles+": "+err.Error())
     } else {
-        for i=range list1 {
-            if list1[i]!=list2[i] {
-                eq=false
-                break
+        Detail("network: "+strconv.Itoa(net.NumNodes())+" nodes, "+strconv.Itoa(net.NumEdges())+" edges")
+        if options.MapFile!="" {
+            Progress("reading mapping: "+options.MapFile)
+            mapping,err=network.ReadMapping(options.MapFile,options.MapOutput)
+            if err!=nil {
+                Error(ErrorCode(err),"pathrider "+command+": "+options.MapFile+": "+err.Error())
+            } else {
+                Progress("mapping nodes")
+                net=net.Map(mapping)
+            }
+        }
+        if (err==nil) && (options.WeightFile!="") {
+            Progress("reading weights: "+options.WeightFile)
+            err=net.ReadWeights(options.WeightFile)
+            if err!=nil {
+                Error(ErrorCode(err),"pathrider "+command+": "+options.WeightFile+": "+err.Error())
+            }
+        }
+        if (err==nil) && options.Confidence {
+            err=net.UseConfidence()
+            if err!=nil {
+                Error(ExitInput,"pathrider "+command+": "+err.Error())
+            }
+        }
+        if (err==nil) && !math.IsNaN(options.MinWeight) {
+            Progress("filtering weights")
+            net,err=net.FilterWeights(options.MinWeight)
+            if err!=nil {
+                Error(ErrorCode(err),"pathrider "+command+": "+err.Error())
+            }
+        }
+        if (err==nil) && ((len(options.Includes)!=0) || (len(options.Excludes)!=0)) {
+            Progress("filtering interactions")
+            include,err=Patterns(options.Includes)
+            if err==nil {
+                exclude,err=Patterns(options.Excludes)
+            }
+            if err==nil {
+                net,err=net.FilterInteractions(include,exclude)
+            }
+            if err!=nil {
+                Error(ErrorCode(err),"pathrider "+command+": "+err.Error())
+            }
+        }
+        if (err==nil) && (options.BlackFile!="") {
+            Progress("reading blacklist: "+options.BlackFile)
+            blackNodes,err=net.ReadNodes(options.BlackFile)
+            if err!=nil {
+                Error(ErrorCode(err),"pathrider "+command+": "+options.BlackFile+": "+err.Error())
+            } else {
+                Progress("blacklisting nodes")
+                blackNeighbours=net.Neighbours(blackNodes)
+                net,err=net.Blacklist(blackNodes)
+                if err!=nil {
+                    Error(ErrorCode(err),"pathrider "+command+": "+options.BlackFile+": "+err.Error())
+                }
+            }
+        }
+        if (err==nil) && (options.BlackEdgeFile!="") {
+            Progress("reading edge blacklist: "+options.BlackEdgeFile)
+            blackEdges,err=net.ReadEdges(options.BlackEdgeFile)
+            if err!=nil {
+                Error(ErrorCode(err),"pathrider "+command+": "+options.BlackEdgeFile+": "+err.Error())
+            } else {
+                Progress("blacklisting edges")
+                net,err=net.BlacklistEdges(blackEdges)
+                if err!=nil {
+                    Error(ErrorCode(err),"pathrider "+command+": "+options.BlackEdgeFile+": "+err.Error())
+                }
             }
         }
     }
-    return eq
+    return net,mapping,blackNeighbours,err
 }
+// WriteText writes text to textFile, one item per line, "-" standing for the
+// standard output.
 func WriteText(textFile string,text []string) error {
     var (
         err error
@@ -85,6 +185,8 @@ func WriteText(textFile string,text []string) error {
     )
     if len(text)==0 {
         err=errors.New("empty before writing")
+    } else if textFile=="-" {
+        _,err=os.Stdout.WriteString(strings.Join(text,"\n")+"\n")
     } else {
         file,err=os.Create(textFile)
         defer file.Close()
@@ -94,3 +196,406 @@ func WriteText(textFile string,text []string) error {
     }
     return err
 }
+// OutFile derives the name of an additional output file from the main one,
+// e.g. OutFile("out.sif","-shortest",".dot") is "out-shortest.dot". When the
+// main output is the standard output ("-"), the additional ones are named
+// after "out" in the current directory, e.g. "out-shortest.dot", with a warning
+// telling where they go.
+func OutFile(outFile,suffix,ext string) string {
+    var (
+        stdout bool
+        file,outFilePath,outFileBase string
+    )
+    stdout=(outFile=="-")
+    if stdout {
+        outFile="out"
+    }
+    outFilePath,outFileBase=filepath.Split(outFile)
+    outFileBase=strings.TrimSuffix(outFileBase,filepath.Ext(outFileBase))
+    outFileBase+=suffix+ext
+    file=filepath.Join(outFilePath,outFileBase)
+    if stdout {
+        Warning("writing "+file+" in the current directory, the main output going to the standard output")
+    }
+    return file
+}
+// NetworkFile derives the name of an additional output network file from the
+// main one, keeping its file format, e.g. NetworkFile("out.graphml","-shortest")
+// is "out-shortest.graphml".
+func NetworkFile(outFile,suffix string) string {
+    var (
+        ext string
+    )
+    ext=".sif"
+    if IsInList([]string{".graphml",".json",".cx2"},filepath.Ext(outFile)) {
+        ext=filepath.Ext(outFile)
+    }
+    return OutFile(outFile,suffix,ext)
+}
+// WriteNetwork writes a network to networkFile, in the file format given by
+// its extension: GraphML (.graphml), cytoscape.js (.json) or CX2 (.cx2), the
+// last two being annotated after highlight, and SIF otherwise.
+func WriteNetwork(net *network.Network,networkFile string,highlight network.Highlight) error {
+    var (
+        err error
+    )
+    if filepath.Ext(networkFile)==".graphml" {
+        err=net.WriteGraphML(networkFile)
+    } else if filepath.Ext(networkFile)==".json" {
+        err=net.WriteCytoscape(networkFile,highlight)
+    } else if filepath.Ext(networkFile)==".cx2" {
+        err=net.WriteCX2(networkFile,highlight)
+    } else {
+        err=net.Write(networkFile)
+    }
+    return err
+}
+// WriteResult writes a result network to outFile and, if writeDOT, to the
+// corresponding DOT file and, if writeSVG, to the corresponding SVG and HTML
+// files, reporting progress as the commands do.
+func WriteResult(command,what string,result *network.Network,outFile string,writeDOT,writeSVG bool,highlight network.Highlight) error {
+    var (
+        err error
+        dotFile,svgFile,htmlFile string
+        drawing *network.Drawing
+    )
+    Progress("writing "+what+": "+outFile)
+    Detail(what+": "+strconv.Itoa(result.NumNodes())+" nodes, "+strconv.Itoa(result.NumEdges())+" edges")
+    err=WriteNetwork(result,outFile,highlight)
+    if err!=nil {
+        Error(ExitIO,"pathrider "+command+": "+outFile+": "+err.Error())
+    }
+    if (err==nil) && writeDOT {
+        dotFile=OutFile(outFile,"",".dot")
+        Progress("writing "+what+": "+dotFile)
+        err=result.WriteDOT(dotFile,highlight)
+        if err!=nil {
+            Error(ExitIO,"pathrider "+command+": "+dotFile+": "+err.Error())
+        }
+    }
+    if (err==nil) && writeSVG {
+        svgFile=OutFile(outFile,"",".svg")
+        Progress("drawing "+what)
+        drawing=result.Draw()
+        Progress("writing "+what+": "+svgFile)
+        err=drawing.WriteSVG(svgFile,highlight)
+        if err!=nil {
+            Error(ExitIO,"pathrider "+command+": "+svgFile+": "+err.Error())
+        } else {
+            htmlFile=OutFile(outFile,"",".html")
+            Progress("writing "+what+": "+htmlFile)
+            err=drawing.WriteHTML(htmlFile,highlight)
+            if err!=nil {
+                Error(ExitIO,"pathrider "+command+": "+htmlFile+": "+err.Error())
+            }
+        }
+    }
+    return err
+}
+// ConnectNodes returns the paths connecting the source nodes to the target
+// nodes computed by Connect, reporting the lack of connecting paths as an
+// empty result, sourceFile and targetFile being the files listing these
+// nodes.
+func ConnectNodes(command string,net *network.Network,sources,targets []string,sourceFile,targetFile string) *network.Network {
+    var (
+        err error
+        intersect *network.Network
+    )
+    Progress("computing connecting paths")
+    intersect,err=net.Connect(sources,targets)
+    if errors.Is(err,network.ErrNoForward) {
+        Empty("pathrider "+command+": "+sourceFile+": "+err.Error())
+    } else if errors.Is(err,network.ErrNoBackward) {
+        Empty("pathrider "+command+": "+targetFile+": "+err.Error())
+    } else if err!=nil {
+        Empty("pathrider "+command+": "+err.Error())
+    }
+    return intersect
+}
+// WriteTerminal writes the upstream or downstream (stream) terminal nodes
+// reachable from the seed nodes listed in seedFile to termFile, warning if
+// there are none without changing the exit code, since the paths were written.
+func WriteTerminal(termFile,stream string,termNodes []string,seedFile string) error {
+    var (
+        err error
+    )
+    if len(termNodes)==0 {
+        Warning("pathrider stream: "+seedFile+": no "+stream+" terminal nodes found")
+    } else {
+        Progress("writing "+stream+" terminal nodes: "+termFile)
+        err=WriteText(termFile,termNodes)
+        if err!=nil {
+            Error(ExitIO,"pathrider stream: "+termFile+": "+err.Error())
+        }
+    }
+    return err
+}
+// EdgeSigns tells, for each edge of result, the overall signs of the
+// connecting paths it lies on (unknown if they all go through unsigned edges),
+// as expected by WriteEdgeTable.
+func EdgeSigns(result,positive,negative *network.Network) map[string]map[string][]string {
+    var (
+        edge,signs []string
+        values map[string]map[string][]string
+    )
+    values=make(map[string]map[string][]string)
+    for _,edge=range result.Edges() {
+        signs=[]string{}
+        if positive.HasEdge(edge[0],edge[1]) {
+            signs=append(signs,"positive")
+        }
+        if negative.HasEdge(edge[0],edge[1]) {
+            signs=append(signs,"negative")
+        }
+        if len(signs)==0 {
+            signs=append(signs,"unknown")
+        }
+        if values[edge[0]]==nil {
+            values[edge[0]]=make(map[string][]string)
+        }
+        values[edge[0]][edge[1]]=[]string{strings.Join(signs,",")}
+    }
+    return values
+}
+// EdgeDirections tells, for each edge of result, whether it is upstream (up),
+// downstream (down) or both (both) of the seed nodes, as expected by
+// WriteEdgeTable.
+func EdgeDirections(result,upward,downward *network.Network) map[string]map[string][]string {
+    var (
+        direction string
+        edge []string
+        values map[string]map[string][]string
+    )
+    values=make(map[string]map[string][]string)
+    for _,edge=range result.Edges() {
+        if upward.HasEdge(edge[0],edge[1]) && downward.HasEdge(edge[0],edge[1]) {
+            direction="both"
+        } else if upward.HasEdge(edge[0],edge[1]) {
+            direction="up"
+        } else {
+            direction="down"
+        }
+        if values[edge[0]]==nil {
+            values[edge[0]]=make(map[string][]string)
+        }
+        values[edge[0]][edge[1]]=[]string{direction}
+    }
+    return values
+}
+// SeedProvenance labels each edge of result, the output of Stream, with the
+// seed nodes it is upstream or downstream of, as expected by WriteProvenance.
+func SeedProvenance(result *network.Network,seeds []string,direction string,depth float64) map[string]map[string][]string {
+    var (
+        seed string
+        edge []string
+        ward *network.Network
+        labels map[string]map[string][]string
+    )
+    labels=make(map[string]map[string][]string)
+    for _,seed=range seeds {
+        ward,_=result.Stream([]string{seed},direction,depth)
+        for _,edge=range ward.Edges() {
+            addLabel(labels,edge,seed)
+        }
+    }
+    return labels
+}
+// PairProvenance labels each edge of result, the output of Connect, with the
+// source/target pairs ("source->target") whose connecting paths it lies on, as
+// expected by WriteProvenance.
+func PairProvenance(result *network.Network,sources,targets []string) map[string]map[string][]string {
+    var (
+        i,j int
+        edge []string
+        forwards,backwards []*network.Network
+        labels map[string]map[string][]string
+    )
+    labels=make(map[string]map[string][]string)
+    for i=range sources {
+        forwards=append(forwards,result.Forward([]string{sources[i]},math.NaN()))
+    }
+    for j=range targets {
+        backwards=append(backwards,result.Backward([]string{targets[j]},math.NaN()))
+    }
+    for _,edge=range result.Edges() {
+        for i=range sources {
+            if forwards[i].HasEdge(edge[0],edge[1]) {
+                for j=range targets {
+                    if backwards[j].HasEdge(edge[0],edge[1]) {
+                        addLabel(labels,edge,sources[i]+"->"+targets[j])
+                    }
+                }
+            }
+        }
+    }
+    return labels
+}
+func addLabel(labels map[string]map[string][]string,edge []string,label string) {
+    if labels[edge[0]]==nil {
+        labels[edge[0]]=make(map[string][]string)
+    }
+    labels[edge[0]][edge[1]]=append(labels[edge[0]][edge[1]],label)
+}
+// WriteProvenance writes the edge labels of result computed by SeedProvenance
+// or PairProvenance to an edge table (tableFile) and a node table (nodeFile),
+// a node getting the labels of its edges. Both tables have the columns column,
+// the comma-separated labels, and count, their number.
+func WriteProvenance(result *network.Network,labels map[string]map[string][]string,column,tableFile,nodeFile string) error {
+    var (
+        err error
+        node,label string
+        edge,nodeLabels []string
+        edgeValues map[string]map[string][]string
+        nodeValues map[string][]string
+        inNodeLabels map[string]map[string]bool
+    )
+    edgeValues=make(map[string]map[string][]string)
+    nodeValues=make(map[string][]string)
+    inNodeLabels=make(map[string]map[string]bool)
+    for _,edge=range result.Edges() {
+        if edgeValues[edge[0]]==nil {
+            edgeValues[edge[0]]=make(map[string][]string)
+        }
+        edgeValues[edge[0]][edge[1]]=[]string{strings.Join(labels[edge[0]][edge[1]],","),strconv.Itoa(len(labels[edge[0]][edge[1]]))}
+        for _,node=range edge {
+            if inNodeLabels[node]==nil {
+                inNodeLabels[node]=make(map[string]bool)
+            }
+            for _,label=range labels[edge[0]][edge[1]] {
+                if !inNodeLabels[node][label] {
+                    inNodeLabels[node][label]=true
+                    nodeValues[node]=append(nodeValues[node],label)
+                }
+            }
+        }
+    }
+    for node,nodeLabels=range nodeValues {
+        nodeValues[node]=[]string{strings.Join(nodeLabels,","),strconv.Itoa(len(nodeLabels))}
+    }
+    Progress("writing provenance: "+tableFile)
+    err=result.WriteEdgeTable(tableFile,[]string{column,"count"},edgeValues)
+    if err==nil {
+        Progress("writing provenance: "+nodeFile)
+        err=result.WriteNodeTable(nodeFile,[]string{column,"count"},nodeValues)
+        if err!=nil {
+            err=errors.New(nodeFile+": "+err.Error())
+        }
+    } else {
+        err=errors.New(tableFile+": "+err.Error())
+    }
+    return err
+}
+// WriteComponents writes the strongly connected components of result to a
+// node table (tableFile) and its condensation to a network file
+// (condensedFile), the condensation being skipped with a warning if it has no
+// edges, which leaves the exit code unchanged since the components are
+// written.
+func WriteComponents(command string,result *network.Network,tableFile,condensedFile string) error {
+    var (
+        err error
+        components [][]string
+        condensed *network.Network
+    )
+    components=result.Components()
+    Detail("components: "+strconv.Itoa(len(components)))
+    Progress("writing components: "+tableFile)
+    err=result.WriteComponents(tableFile,components)
+    if err!=nil {
+        err=errors.New(tableFile+": "+err.Error())
+    } else {
+        condensed=result.Condense(components)
+        if condensed.NumEdges()==0 {
+            Warning("pathrider "+command+": no edges between the strongly connected components, condensation not written")
+        } else {
+            Progress("writing condensation: "+condensedFile)
+            Detail("condensation: "+strconv.Itoa(condensed.NumNodes())+" nodes, "+strconv.Itoa(condensed.NumEdges())+" edges")
+            err=WriteNetwork(condensed,condensedFile,network.Highlight{})
+            if err!=nil {
+                err=errors.New(condensedFile+": "+err.Error())
+            }
+        }
+    }
+    return err
+}
+// ReportMapping reports the number of identifiers kept as is while mapping the
+// network and the node files, because they are ambiguous or unmapped, and
+// lists them in tableFile.
+func ReportMapping(command string,mapping *network.Mapping,tableFile string) error {
+    var (
+        err error
+    )
+    Progress("mapping: "+strconv.Itoa(len(mapping.Ambiguous()))+" ambiguous and "+strconv.Itoa(len(mapping.Unmapped()))+" unmapped identifiers kept as is")
+    Progress("writing mapping report: "+tableFile)
+    err=mapping.WriteReport(tableFile)
+    if err!=nil {
+        Error(ExitIO,"pathrider "+command+": "+tableFile+": "+err.Error())
+    }
+    return err
+}
+// StringList is a flag which can be given several times.
+type StringList []string
+func (list *StringList) String() string {
+    return strings.Join(*list,",")
+}
+func (list *StringList) Set(value string) error {
+    *list=append(*list,value)
+    return nil
+}
+// Patterns compiles interaction name patterns given on the command line,
+// "@file" standing for the patterns listed in file.
+func Patterns(values []string) ([]*regexp.Regexp,error) {
+    var (
+        err error
+        value string
+        compiled *regexp.Regexp
+        patterns,filePatterns []*regexp.Regexp
+    )
+    for _,value=range values {
+        if strings.HasPrefix(value,"@") {
+            filePatterns,err=network.ReadPatterns(value[1:])
+            if err!=nil {
+                err=fmt.Errorf("%s: %w",value[1:],err)
+                break
+            }
+            patterns=append(patterns,filePatterns...)
+        } else {
+            compiled,err=network.CompilePattern(value)
+            if err!=nil {
+                break
+            }
+            patterns=append(patterns,compiled)
+        }
+    }
+    return patterns,err
+}
+// StdinOnce tells whether at most one of files is "-", the standard input
+// being readable only once. Each of files can be a comma-separated list, as the
+// network files are.
+func StdinOnce(files []string) bool {
+    var (
+        n int
+        file,name string
+    )
+    for _,file=range files {
+        for _,name=range strings.Split(file,",") {
+            if name=="-" {
+                n+=1
+            }
+        }
+    }
+    return n<=1
+}
+func IsInList(list []string,thatElement string) bool {
+    var (
+        found bool
+        element string
+    )
+    found=false
+    for _,element=range list {
+        if element==thatElement {
+            found=true
+            break
+        }
+    }
+    return found
+}